@@ -0,0 +1,104 @@
+package tvdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseAirsTime parses the time of day a series airs.  TheTVDB uses several
+// formats such as "9:00 PM", "9pm", "8:30 p.m.", and "21:00".
+func parseAirsTime(s string) (hour, minute int, err error) {
+	invalid := fmt.Errorf("tvdb: invalid air time '%s'", s)
+
+	t := strings.ToLower(s)
+	t = strings.NewReplacer(" ", "", ".", "").Replace(t)
+
+	meridiem := ""
+	if strings.HasSuffix(t, "am") || strings.HasSuffix(t, "pm") {
+		meridiem = t[len(t)-2:]
+		t = t[:len(t)-2]
+	}
+
+	parts := strings.SplitN(t, ":", 2)
+	if hour, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, invalid
+	}
+	if len(parts) == 2 {
+		if len(parts[1]) != 2 {
+			return 0, 0, invalid
+		}
+		if minute, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, 0, invalid
+		}
+	}
+
+	switch meridiem {
+	case "":
+		if hour > 23 {
+			return 0, 0, invalid
+		}
+	default:
+		if hour < 1 || hour > 12 {
+			return 0, 0, invalid
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	if hour < 0 || minute < 0 || minute > 59 {
+		return 0, 0, invalid
+	}
+	return hour, minute, nil
+}
+
+// AirsTimeParsed returns the hour (0-23) and minute the series airs from
+// AirsTime.
+func (s *Series) AirsTimeParsed() (hour, minute int, err error) {
+	return parseAirsTime(s.AirsTime)
+}
+
+// AirsWeekday returns the day of the week the series airs from
+// AirsDayOfWeek.  ok is false if it isn't a single day such as "Daily".
+func (s *Series) AirsWeekday() (day time.Weekday, ok bool) {
+	name := strings.TrimSpace(s.AirsDayOfWeek)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(name, d.String()) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// NextAirDateTime returns the next time after now the series is scheduled to
+// air, taking AirsDayOfWeek and AirsTime as wall clock times in loc (UTC if
+// nil).  ok is false if the series has ended or its schedule can't be
+// parsed.
+func (s *Series) NextAirDateTime(now time.Time, loc *time.Location) (next time.Time, ok bool) {
+	if s.IsEnded() {
+		return time.Time{}, false
+	}
+	day, ok := s.AirsWeekday()
+	if !ok {
+		return time.Time{}, false
+	}
+	hour, minute, err := s.AirsTimeParsed()
+	if err != nil {
+		return time.Time{}, false
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// Build the slot from the date so it stays at the same wall clock time
+	// across DST changes
+	local := now.In(loc)
+	days := (int(day) - int(local.Weekday()) + 7) % 7
+	next = time.Date(local.Year(), local.Month(), local.Day()+days, hour, minute, 0, 0, loc)
+	if !next.After(now) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+days+7, hour, minute, 0, 0, loc)
+	}
+	return next, true
+}
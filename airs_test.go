@@ -0,0 +1,102 @@
+package tvdb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAirsTimeParsed(t *testing.T) {
+	tests := map[string][2]int{
+		"9:00 PM":   {21, 0},
+		"9:30PM":    {21, 30},
+		"9pm":       {21, 0},
+		"8:30 p.m.": {20, 30},
+		"12:00 AM":  {0, 0},
+		"12:15 PM":  {12, 15},
+		"21:00":     {21, 0},
+		"07:05":     {7, 5},
+	}
+	for airsTime, want := range tests {
+		s := &Series{AirsTime: airsTime}
+		hour, minute, err := s.AirsTimeParsed()
+		if err != nil {
+			t.Errorf("Air time '%s' unexpected error '%v'", airsTime, err)
+			continue
+		}
+		if hour != want[0] || minute != want[1] {
+			t.Errorf("Air time '%s' expected '%02d:%02d' got '%02d:%02d'", airsTime, want[0], want[1], hour, minute)
+		}
+	}
+
+	for _, airsTime := range []string{"", "noon", "13pm", "25:00", "9:5 PM", "9:60"} {
+		s := &Series{AirsTime: airsTime}
+		if _, _, err := s.AirsTimeParsed(); err == nil {
+			t.Errorf("Air time '%s' expected an error", airsTime)
+		}
+	}
+}
+
+func TestAirsWeekday(t *testing.T) {
+	tests := map[string]time.Weekday{
+		"Sunday":     time.Sunday,
+		"thursday ":  time.Thursday,
+		"SATURDAY":   time.Saturday,
+		"Daily":      -1,
+		"":           -1,
+		"Weekdays":   -1,
+		"Wednesdays": -1,
+	}
+	for name, want := range tests {
+		s := &Series{AirsDayOfWeek: name}
+		day, ok := s.AirsWeekday()
+		if want < 0 {
+			if ok {
+				t.Errorf("Day '%s' expected no weekday got '%s'", name, day)
+			}
+			continue
+		}
+		if !ok || day != want {
+			t.Errorf("Day '%s' expected '%s' got '%s' (%v)", name, want, day, ok)
+		}
+	}
+}
+
+func TestNextAirDateTime(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	s := &Series{Status: "Continuing", AirsDayOfWeek: "Sunday", AirsTime: "8:00 PM"}
+
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		// Wednesday rolls forward to Sunday
+		{time.Date(2014, 6, 4, 12, 0, 0, 0, loc), time.Date(2014, 6, 8, 20, 0, 0, 0, loc)},
+		// Sunday before the slot airs the same day
+		{time.Date(2014, 6, 8, 19, 59, 0, 0, loc), time.Date(2014, 6, 8, 20, 0, 0, 0, loc)},
+		// Sunday at the slot moves to the next week
+		{time.Date(2014, 6, 8, 20, 0, 0, 0, loc), time.Date(2014, 6, 15, 20, 0, 0, 0, loc)},
+		// Monday 01:00 UTC is still Sunday evening in New York
+		{time.Date(2014, 6, 9, 1, 0, 0, 0, time.UTC), time.Date(2014, 6, 15, 20, 0, 0, 0, loc)},
+		// The slot keeps its wall clock time across the end of DST
+		{time.Date(2014, 10, 30, 12, 0, 0, 0, loc), time.Date(2014, 11, 2, 20, 0, 0, 0, loc)},
+	}
+	for _, test := range tests {
+		got, ok := s.NextAirDateTime(test.now, loc)
+		if !ok || !got.Equal(test.want) {
+			t.Errorf("Now '%s' expected '%s' got '%s' (%v)", test.now, test.want, got, ok)
+		}
+	}
+
+	for _, s := range []*Series{
+		{Status: "Ended", AirsDayOfWeek: "Sunday", AirsTime: "8:00 PM"},
+		{Status: "Continuing", AirsDayOfWeek: "Daily", AirsTime: "8:00 PM"},
+		{Status: "Continuing", AirsDayOfWeek: "Sunday", AirsTime: ""},
+	} {
+		if got, ok := s.NextAirDateTime(time.Now(), loc); ok {
+			t.Errorf("Series '%s' '%s' '%s' expected no air time got '%s'", s.Status, s.AirsDayOfWeek, s.AirsTime, got)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package tvdb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// BannerList is a list of banners such as all of the artwork for a series as
+// returned by BannersBySeries.
+type BannerList []Banner
+
+// ByType returns the banners of the given type such as BannerPoster.
+func (l BannerList) ByType(t BannerType) []*Banner {
+	banners := []*Banner{}
+	for i := range l {
+		if l[i].BannerType == t {
+			banners = append(banners, &l[i])
+		}
+	}
+	return banners
+}
+
+// BySeason returns the season banners for a season.  Banners of other types
+// and banners without a season are ignored.
+func (l BannerList) BySeason(season int) []*Banner {
+	banners := []*Banner{}
+	for i := range l {
+		b := &l[i]
+		if b.BannerType == BannerSeason && b.Season.Valid && b.Season.Value == season {
+			banners = append(banners, b)
+		}
+	}
+	return banners
+}
+
+// Size returns the width and height in pixels of fanart and posters from
+// their BannerType2 such as "1920x1080".  ok is false for other banners whose
+// BannerType2 is a style like "graphical" instead.
+func (b *Banner) Size() (width, height int, ok bool) {
+	parts := strings.SplitN(b.BannerType2, "x", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	w, wErr := strconv.Atoi(parts[0])
+	h, hErr := strconv.Atoi(parts[1])
+	if wErr != nil || hErr != nil {
+		return 0, 0, false
+	}
+	return w, h, true
+}
+
+// SeasonPoster returns the highest rated poster for a season.  Wide season
+// banners are ignored.
+func (l BannerList) SeasonPoster(season int) (*Banner, bool) {
+	var best *Banner
+	for _, b := range l.BySeason(season) {
+		if b.BannerType2 == "seasonwide" {
+			continue
+		}
+		if best == nil || ratedHigher(b, best) {
+			best = b
+		}
+	}
+	return best, best != nil
+}
+
+// ratedHigher reports whether a has a better rating than b.  Banners without a
+// rating are rated lowest.
+func ratedHigher(a, b *Banner) bool {
+	if !a.Rating.Valid {
+		return false
+	}
+	return !b.Rating.Valid || a.Rating.Value > b.Rating.Value
+}
@@ -0,0 +1,97 @@
+package tvdb
+
+import (
+	"reflect"
+	"testing"
+)
+
+// bannerIDs returns the IDs of banners in order.
+func bannerIDs(banners []*Banner) []int {
+	ids := []int{}
+	for _, b := range banners {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+func TestBannerListByType(t *testing.T) {
+	banners := BannerList{
+		{ID: 1, BannerType: BannerFanart},
+		{ID: 2, BannerType: BannerPoster},
+		{ID: 3, BannerType: BannerSeason, Season: NullInt(1)},
+		{ID: 4, BannerType: BannerPoster},
+	}
+
+	want := map[BannerType][]int{
+		BannerPoster: {2, 4},
+		BannerSeason: {3},
+		BannerSeries: {},
+	}
+	for bannerType, ids := range want {
+		if got := bannerIDs(banners.ByType(bannerType)); !reflect.DeepEqual(got, ids) {
+			t.Errorf("Banner type '%s' expected '%v' got '%v'", bannerType, ids, got)
+		}
+	}
+}
+
+func TestBannerListBySeason(t *testing.T) {
+	banners := BannerList{
+		{ID: 1, BannerType: BannerSeason, Season: NullInt(1)},
+		{ID: 2, BannerType: BannerSeason, Season: NullInt(2)},
+		{ID: 3, BannerType: BannerSeason, Season: NullInt(1)},
+		{ID: 4, BannerType: BannerFanart, Season: NullInt(1)},
+		{ID: 5, BannerType: BannerSeason, Season: NulInt},
+	}
+
+	want := map[int][]int{
+		0: {},
+		1: {1, 3},
+		2: {2},
+	}
+	for season, ids := range want {
+		if got := bannerIDs(banners.BySeason(season)); !reflect.DeepEqual(got, ids) {
+			t.Errorf("Season '%d' expected '%v' got '%v'", season, ids, got)
+		}
+	}
+}
+
+func TestBannerSize(t *testing.T) {
+	tests := []struct {
+		bannerType2 string
+		w, h        int
+		ok          bool
+	}{
+		{"1920x1080", 1920, 1080, true},
+		{"680x1000", 680, 1000, true},
+		{"graphical", 0, 0, false},
+		{"seasonwide", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		b := &Banner{BannerType2: test.bannerType2}
+		if w, h, ok := b.Size(); w != test.w || h != test.h || ok != test.ok {
+			t.Errorf("BannerType2 '%s' expected '%dx%d' (%v) got '%dx%d' (%v)", test.bannerType2, test.w, test.h, test.ok, w, h, ok)
+		}
+	}
+}
+
+func TestSeasonPoster(t *testing.T) {
+	banners := BannerList{
+		{ID: 1, BannerType: BannerSeason, BannerType2: "season", Season: NullInt(1), Rating: NulFloat64},
+		{ID: 2, BannerType: BannerSeason, BannerType2: "season", Season: NullInt(1), Rating: NullFloat64(7.5)},
+		{ID: 3, BannerType: BannerSeason, BannerType2: "seasonwide", Season: NullInt(1), Rating: NullFloat64(9)},
+		{ID: 4, BannerType: BannerSeason, BannerType2: "season", Season: NullInt(1), Rating: NullFloat64(6)},
+		{ID: 5, BannerType: BannerSeason, BannerType2: "season", Season: NullInt(2), Rating: NulFloat64},
+	}
+
+	want := map[int]int{1: 2, 2: 5}
+	for season, id := range want {
+		if b, ok := banners.SeasonPoster(season); !ok || b.ID != id {
+			t.Errorf("Season '%d' expected banner '%d' got '%v' (%v)", season, id, b, ok)
+		}
+	}
+	if _, ok := banners.SeasonPoster(3); ok {
+		t.Error("Expected no poster for a season without banners")
+	}
+}
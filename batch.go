@@ -0,0 +1,169 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultConcurrency is the number of requests batch calls make at once when
+// the Client has no Concurrency set.
+const DefaultConcurrency = 4
+
+// concurrency returns the configured Concurrency or DefaultConcurrency.
+func (c *Client) concurrency() int {
+	if c.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return c.Concurrency
+}
+
+// forEach calls fn for every index in [0, n) from a pool of at most
+// Concurrency goroutines.  The first error cancels the context given to the
+// remaining calls, stops any new ones from starting, and is returned.  If the
+// caller cancels ctx its error is returned instead of any errors the
+// cancellation caused.
+func (c *Client) forEach(parent context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	workers := c.concurrency()
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	indexes := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(ctx, i); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := parent.Err(); err != nil {
+		return err
+	}
+	return firstErr
+}
+
+// SeriesError records a failure to fetch a single series during a batch call
+// so the series that were skipped can be identified.
+type SeriesError struct {
+	ID  int
+	Err error
+}
+
+func (e *SeriesError) Error() string {
+	return fmt.Sprintf("Failed to get series '%d': %v", e.ID, e.Err)
+}
+
+// Unwrap allows errors.Is and errors.As to match the underlying error.
+func (e *SeriesError) Unwrap() error {
+	return e.Err
+}
+
+// SeriesDetails gets the full details for each of the series summaries
+// returned by a search such as SearchSeries.  Up to Concurrency requests are
+// made at once and the results are in the same order as summaries.  The first
+// failed request cancels the rest and its error is returned as a
+// *SeriesError.
+func (c *Client) SeriesDetails(ctx context.Context, summaries []SeriesSummary, lang string) ([]*Series, error) {
+	series := make([]*Series, len(summaries))
+	err := c.forEach(ctx, len(summaries), func(ctx context.Context, i int) error {
+		s, err := c.SeriesByID(ctx, summaries[i].ID, lang)
+		if err != nil {
+			return &SeriesError{ID: summaries[i].ID, Err: err}
+		}
+		series[i] = s
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// SeriesDetailsPartial is like SeriesDetails but keeps going when requests
+// fail.  Series that couldn't be fetched are left nil so the results still
+// line up with summaries and the failures are returned as *SeriesError values
+// joined into a single error.
+func (c *Client) SeriesDetailsPartial(ctx context.Context, summaries []SeriesSummary, lang string) ([]*Series, error) {
+	ids := make([]int, len(summaries))
+	for i := range summaries {
+		ids[i] = summaries[i].ID
+	}
+	return c.SeriesByIDs(ctx, ids, lang)
+}
+
+// SeriesByIDs gets the series for each of ids with up to Concurrency requests
+// at once.  The results are in the same order as ids.  Series that couldn't
+// be fetched, such as IDs that don't exist, are left nil and the failures are
+// returned as *SeriesError values joined into a single error.
+func (c *Client) SeriesByIDs(ctx context.Context, ids []int, lang string) ([]*Series, error) {
+	series := make([]*Series, len(ids))
+	errs := make([]error, len(ids))
+	err := c.forEach(ctx, len(ids), func(ctx context.Context, i int) error {
+		s, err := c.SeriesByID(ctx, ids[i], lang)
+		if err != nil {
+			errs[i] = &SeriesError{ID: ids[i], Err: err}
+			return nil
+		}
+		series[i] = s
+		return nil
+	})
+	if err != nil {
+		return series, err
+	}
+	return series, errors.Join(errs...)
+}
+
+// SeriesByIDLangs gets a series in each of langs with up to Concurrency
+// requests at once.  The results are keyed by language and a language the
+// series isn't available in maps to nil.  Other failures also map to nil and
+// are returned as *SeriesError values joined into a single error.
+func (c *Client) SeriesByIDLangs(ctx context.Context, id int, langs []string) (map[string]*Series, error) {
+	series := make([]*Series, len(langs))
+	errs := make([]error, len(langs))
+	err := c.forEach(ctx, len(langs), func(ctx context.Context, i int) error {
+		s, err := c.SeriesByID(ctx, id, langs[i])
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			errs[i] = &SeriesError{ID: id, Err: err}
+		}
+		series[i] = s
+		return nil
+	})
+
+	byLang := make(map[string]*Series, len(langs))
+	for i, lang := range langs {
+		byLang[lang] = series[i]
+	}
+	if err != nil {
+		return byLang, err
+	}
+	return byLang, errors.Join(errs...)
+}
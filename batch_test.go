@@ -0,0 +1,203 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// handleSeries serves a minimal series document for every series ID in ids.
+func handleSeries(ids ...int) {
+	for _, id := range ids {
+		id := id
+		mux.HandleFunc(fmt.Sprintf("/api/%s/series/%d/en.xml", apiKey, id), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8" ?><Data><Series><id>%d</id><SeriesName>Series %d</SeriesName></Series></Data>`, id, id)
+		})
+	}
+}
+
+func TestSeriesDetails(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Concurrency = 2
+
+	ids := []int{5, 4, 3, 2, 1}
+	handleSeries(ids...)
+
+	summaries := []SeriesSummary{}
+	for _, id := range ids {
+		summaries = append(summaries, SeriesSummary{ID: id})
+	}
+
+	series, err := client.SeriesDetails(context.Background(), summaries, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range series {
+		if s.ID != ids[i] {
+			t.Errorf("Series '%d' expected ID '%d' got '%d'", i, ids[i], s.ID)
+		}
+	}
+}
+
+func TestSeriesDetailsError(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Concurrency = 1
+
+	// Series 2 is missing so it and everything queued after it fails
+	var mu sync.Mutex
+	requested := []string{}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		if strings.Contains(r.URL.Path, "/series/2/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8" ?><Data><Series><id>1</id></Series></Data>`)
+	})
+
+	summaries := []SeriesSummary{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	_, err := client.SeriesDetails(context.Background(), summaries, "en")
+	var seriesErr *SeriesError
+	if !errors.As(err, &seriesErr) || seriesErr.ID != 2 {
+		t.Fatalf("Expected an error for missing series '2' got '%v'", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requested) > 3 {
+		t.Errorf("Expected remaining requests to be cancelled got '%v'", requested)
+	}
+}
+
+func TestSeriesDetailsPartial(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Concurrency = 1
+
+	handleSeries(1, 3, 4)
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/2/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	summaries := []SeriesSummary{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	series, err := client.SeriesDetailsPartial(context.Background(), summaries, "en")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+	var seriesErr *SeriesError
+	if !errors.As(err, &seriesErr) || seriesErr.ID != 2 {
+		t.Errorf("Expected an error for series '2' got '%v'", err)
+	}
+	if len(series) != len(summaries) {
+		t.Fatalf("Expected '%d' results got '%d'", len(summaries), len(series))
+	}
+	for i, s := range series {
+		if i == 1 {
+			if s != nil {
+				t.Errorf("Expected no series for the failed request got '%v'", s)
+			}
+			continue
+		}
+		if s == nil || s.ID != summaries[i].ID {
+			t.Errorf("Series '%d' expected ID '%d' got '%v'", i, summaries[i].ID, s)
+		}
+	}
+}
+
+func TestSeriesDetailsCancel(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Concurrency = 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Every request blocks until the batch is cancelled
+	var mu sync.Mutex
+	requests := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		cancel()
+		<-r.Context().Done()
+	})
+
+	summaries := []SeriesSummary{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	if _, err := client.SeriesDetails(ctx, summaries, "en"); err != context.Canceled {
+		t.Errorf("Expected '%v' got '%v'", context.Canceled, err)
+	}
+	if _, err := client.SeriesDetailsPartial(ctx, summaries, "en"); err != context.Canceled {
+		t.Errorf("Expected '%v' got '%v'", context.Canceled, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requests > client.Concurrency {
+		t.Errorf("Expected at most '%d' requests got '%d'", client.Concurrency, requests)
+	}
+}
+
+func TestSeriesByIDs(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Concurrency = 3
+
+	handleSeries(1, 2, 4, 5)
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/3/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	ids := []int{5, 4, 3, 2, 1}
+	series, err := client.SeriesByIDs(context.Background(), ids, "en")
+	var seriesErr *SeriesError
+	if !errors.As(err, &seriesErr) || seriesErr.ID != 3 {
+		t.Errorf("Expected an error for missing series '3' got '%v'", err)
+	}
+	for i, s := range series {
+		if ids[i] == 3 {
+			if s != nil {
+				t.Errorf("Expected no series for the missing ID got '%v'", s)
+			}
+			continue
+		}
+		if s == nil || s.ID != ids[i] {
+			t.Errorf("Series '%d' expected ID '%d' got '%v'", i, ids[i], s)
+		}
+	}
+}
+
+func TestSeriesByIDLangs(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	for _, lang := range []string{"en", "de"} {
+		lang := lang
+		mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/%s.xml", apiKey, lang), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8" ?><Data><Series><id>71663</id><language>%s</language></Series></Data>`, lang)
+		})
+	}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/fr.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	series, err := client.SeriesByIDLangs(context.Background(), 71663, []string{"en", "de", "fr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lang := range []string{"en", "de"} {
+		if s := series[lang]; s == nil || s.Language != lang {
+			t.Errorf("Expected series in '%s' got '%v'", lang, s)
+		}
+	}
+	if s, ok := series["fr"]; !ok || s != nil {
+		t.Errorf("Expected no series in 'fr' got '%v' (%v)", s, ok)
+	}
+}
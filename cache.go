@@ -0,0 +1,71 @@
+package tvdb
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache stores raw API responses keyed by the request URL.  Responses are
+// decoded again on every hit so callers are free to modify the values
+// returned from a Client.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, val []byte)
+}
+
+type cacheItem struct {
+	val     []byte
+	expires time.Time
+}
+
+// MemoryCache is an in-memory Cache that expires entries after a fixed TTL.
+// It is safe for concurrent use.
+type MemoryCache struct {
+	ttl   time.Duration
+	mu    sync.Mutex
+	items map[string]cacheItem
+}
+
+// NewMemoryCache returns a MemoryCache that keeps entries for ttl.
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		ttl:   ttl,
+		items: make(map[string]cacheItem),
+	}
+}
+
+// Get returns the value stored for key if it has not expired.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(item.expires) {
+		delete(m.items, key)
+		return nil, false
+	}
+	return item.val, true
+}
+
+// Set stores val for key replacing any existing entry.
+func (m *MemoryCache) Set(key string, val []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.items[key] = cacheItem{
+		val:     val,
+		expires: time.Now().Add(m.ttl),
+	}
+}
+
+// Close drops every entry.  The cache can still be used afterwards.
+func (m *MemoryCache) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.items = make(map[string]cacheItem)
+	return nil
+}
@@ -0,0 +1,86 @@
+package tvdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(time.Hour)
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Expected a miss for an unknown key")
+	}
+
+	cache.Set("key", []byte("value"))
+	if got, ok := cache.Get("key"); !ok || string(got) != "value" {
+		t.Errorf("Expected 'value' got '%s' (%v)", got, ok)
+	}
+
+	expired := NewMemoryCache(-time.Second)
+	expired.Set("key", []byte("value"))
+	if _, ok := expired.Get("key"); ok {
+		t.Error("Expected a miss for an expired key")
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Cache = NewMemoryCache(time.Hour)
+
+	requests := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+
+	langs, err := client.Languages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	langs[0].Name = "Modified"
+
+	cached, err := client.Languages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected '1' request got '%d'", requests)
+	}
+	if cached[0].Name == "Modified" {
+		t.Error("Cached response was modified by the caller")
+	}
+}
+
+func TestClientClose(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	cache := NewMemoryCache(time.Hour)
+	client.Cache = cache
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	key := fmt.Sprintf("%s/api/%s/languages.xml", server.URL, apiKey)
+	if _, ok := cache.Get(key); !ok {
+		t.Fatal("Expected the response to be cached")
+	}
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get(key); ok {
+		t.Error("Expected the cache to be cleared on close")
+	}
+
+	// The client is still usable after closing
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,354 @@
+package tvdb
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// EpisodeList is a list of episodes such as every episode of a series as
+// returned by SeriesAllByID.
+//
+// Methods other than the Sort methods never modify the list.  Groupings such
+// as DVDSeasons are built fresh on every call so a list can be shared between
+// goroutines as long as nothing writes to it.  The returned episodes point
+// into the list.
+type EpisodeList []Episode
+
+// Seasons groups the episodes into seasons numbered by the given order.
+//
+//   - OrderDefault groups by SeasonNumber sorted by EpisodeNumber.
+//   - OrderDVD groups by DVDSeason sorted by DVDEpisodeNumber like DVDSeasons.
+//   - OrderAbsolute has no seasons so every episode with an AbsoluteNumber is
+//     put in season 1 sorted by AbsoluteNumber.
+//
+// Any other order is treated as OrderDefault.
+func (l EpisodeList) Seasons(order Order) map[int][]*Episode {
+	switch order {
+	case OrderDVD:
+		return l.DVDSeasons()
+	case OrderAbsolute:
+		episodes := []*Episode{}
+		for i := range l {
+			if l[i].AbsoluteNumber.Valid {
+				episodes = append(episodes, &l[i])
+			}
+		}
+		sort.SliceStable(episodes, func(i, j int) bool {
+			return episodes[i].AbsoluteNumber.Value < episodes[j].AbsoluteNumber.Value
+		})
+		seasons := make(map[int][]*Episode)
+		if len(episodes) > 0 {
+			seasons[1] = episodes
+		}
+		return seasons
+	}
+
+	seasons := make(map[int][]*Episode)
+	for i := range l {
+		ep := &l[i]
+		seasons[ep.SeasonNumber] = append(seasons[ep.SeasonNumber], ep)
+	}
+	for _, eps := range seasons {
+		sort.SliceStable(eps, func(i, j int) bool {
+			return eps[i].EpisodeNumber < eps[j].EpisodeNumber
+		})
+	}
+	return seasons
+}
+
+// Season is a season of a series with the episodes in it.
+type Season struct {
+	Number   int
+	SeasonID int
+	Episodes []*Episode
+}
+
+// SeasonList groups the episodes into seasons like Seasons(OrderDefault) but
+// keeps TheTVDB's ID for each season.  Seasons are sorted by number starting
+// with specials in season 0.  SeasonID is that of the first episode as every
+// episode in a season shares it.
+func (l EpisodeList) SeasonList() []*Season {
+	seasons := []*Season{}
+	for number, episodes := range l.Seasons(OrderDefault) {
+		seasons = append(seasons, &Season{
+			Number:   number,
+			SeasonID: episodes[0].SeasonID,
+			Episodes: episodes,
+		})
+	}
+	sort.Slice(seasons, func(i, j int) bool {
+		return seasons[i].Number < seasons[j].Number
+	})
+	return seasons
+}
+
+// DVDSeasons groups the episodes by their DVD season number.  Episodes in
+// each season are sorted by their DVD episode number.  Episodes without a
+// DVD season are omitted and episodes with a blank or unparseable DVD episode
+// number are sorted to the end of their season.
+func (l EpisodeList) DVDSeasons() map[int][]*Episode {
+	seasons := make(map[int][]*Episode)
+	for i := range l {
+		ep := &l[i]
+		if !ep.DVDSeason.Valid {
+			continue
+		}
+		seasons[ep.DVDSeason.Value] = append(seasons[ep.DVDSeason.Value], ep)
+	}
+
+	for _, eps := range seasons {
+		sort.SliceStable(eps, func(i, j int) bool {
+			a, aErr := strconv.ParseFloat(eps[i].DVDEpisodeNumber, 64)
+			b, bErr := strconv.ParseFloat(eps[j].DVDEpisodeNumber, 64)
+			if aErr != nil || bErr != nil {
+				return aErr == nil && bErr != nil
+			}
+			return a < b
+		})
+	}
+	return seasons
+}
+
+// ByAbsoluteNumber returns the episode with the given absolute episode
+// number.  Episodes without an absolute number are ignored.
+func (l EpisodeList) ByAbsoluteNumber(n int) (*Episode, bool) {
+	for i := range l {
+		if l[i].AbsoluteNumber.Valid && l[i].AbsoluteNumber.Value == n {
+			return &l[i], true
+		}
+	}
+	return nil, false
+}
+
+// Episode returns the episode with the given season and episode number
+// without making any requests.
+func (l EpisodeList) Episode(season, number int) (*Episode, bool) {
+	for i := range l {
+		if l[i].SeasonNumber == season && l[i].EpisodeNumber == number {
+			return &l[i], true
+		}
+	}
+	return nil, false
+}
+
+// NumberOfSeasons returns the number of distinct seasons in the list.  Season
+// 0, which holds specials, is only counted if includeSpecials is true.
+func (l EpisodeList) NumberOfSeasons(includeSpecials bool) int {
+	seasons := make(map[int]bool)
+	for _, ep := range l {
+		if ep.SeasonNumber == 0 && !includeSpecials {
+			continue
+		}
+		seasons[ep.SeasonNumber] = true
+	}
+	return len(seasons)
+}
+
+// NumberOfEpisodes returns the number of episodes in the list including
+// specials.
+func (l EpisodeList) NumberOfEpisodes() int {
+	return len(l)
+}
+
+// SortedSeasons returns the season numbers in the list in ascending order.
+// Season 0, which holds specials, is always first when present.
+func (l EpisodeList) SortedSeasons() []int {
+	seen := make(map[int]bool)
+	seasons := []int{}
+	for _, ep := range l {
+		if !seen[ep.SeasonNumber] {
+			seen[ep.SeasonNumber] = true
+			seasons = append(seasons, ep.SeasonNumber)
+		}
+	}
+	sort.Ints(seasons)
+	return seasons
+}
+
+// RegularSeasons returns the season numbers in the list in ascending order
+// without season 0, which holds specials.
+func (l EpisodeList) RegularSeasons() []int {
+	seasons := l.SortedSeasons()
+	if len(seasons) > 0 && seasons[0] == 0 {
+		return seasons[1:]
+	}
+	return seasons
+}
+
+// Specials returns the episodes in season 0 sorted by episode number.
+func (l EpisodeList) Specials() []*Episode {
+	return l.EpisodesInSeason(0)
+}
+
+// EpisodesInSeason returns the episodes of a season sorted by episode number.
+func (l EpisodeList) EpisodesInSeason(season int) []*Episode {
+	episodes := []*Episode{}
+	for i := range l {
+		if l[i].SeasonNumber == season {
+			episodes = append(episodes, &l[i])
+		}
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return episodes[i].EpisodeNumber < episodes[j].EpisodeNumber
+	})
+	return episodes
+}
+
+// NextAiredEpisode returns the episode with the earliest air date strictly
+// after the given time.  Episodes without an air date are ignored.
+func (l EpisodeList) NextAiredEpisode(after time.Time) (*Episode, bool) {
+	var next *Episode
+	for i := range l {
+		ep := &l[i]
+		if ep.FirstAired.IsZero() || !ep.FirstAired.After(after) {
+			continue
+		}
+		if next == nil || ep.FirstAired.Before(next.FirstAired.Time) {
+			next = ep
+		}
+	}
+	return next, next != nil
+}
+
+// LatestAiredEpisode returns the episode with the most recent air date at or
+// before the given time.  Episodes without an air date are ignored.
+func (l EpisodeList) LatestAiredEpisode(before time.Time) (*Episode, bool) {
+	var latest *Episode
+	for i := range l {
+		ep := &l[i]
+		if ep.FirstAired.IsZero() || ep.FirstAired.After(before) {
+			continue
+		}
+		if latest == nil || ep.FirstAired.After(latest.FirstAired.Time) {
+			latest = ep
+		}
+	}
+	return latest, latest != nil
+}
+
+// FilterAiredBetween returns the episodes that aired between start and end
+// inclusive in list order.  Episodes without an air date are ignored.
+func (l EpisodeList) FilterAiredBetween(start, end time.Time) []*Episode {
+	episodes := []*Episode{}
+	for i := range l {
+		aired := l[i].FirstAired
+		if aired.IsZero() || aired.Before(start) || aired.After(end) {
+			continue
+		}
+		episodes = append(episodes, &l[i])
+	}
+	return episodes
+}
+
+// Merge returns a new list of the episodes in l updated from other, such as a
+// fresh fetch of the same series.  Episodes are matched by ID so merging the
+// same episodes again never duplicates them.  Episodes from other replace
+// those in l with the same ID and the rest are added at the end.
+func (l EpisodeList) Merge(other EpisodeList) EpisodeList {
+	merged := make(EpisodeList, len(l), len(l)+len(other))
+	copy(merged, l)
+
+	index := make(map[int]int, len(merged))
+	for i := range merged {
+		index[merged[i].ID] = i
+	}
+	for _, ep := range other {
+		if i, ok := index[ep.ID]; ok {
+			merged[i] = ep
+			continue
+		}
+		index[ep.ID] = len(merged)
+		merged = append(merged, ep)
+	}
+	return merged
+}
+
+// Sorted returns every episode in the list ordered by season and then episode
+// number without modifying the list.  Specials in season 0 come first.
+func (l EpisodeList) Sorted() []*Episode {
+	episodes := make([]*Episode, len(l))
+	for i := range l {
+		episodes[i] = &l[i]
+	}
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return seasonEpisodeLess(episodes[i], episodes[j])
+	})
+	return episodes
+}
+
+// SortByAired sorts the list in place by air date, oldest first.  Episodes
+// without an air date are moved to the end and episodes that aired on the
+// same day keep their order.
+func (l EpisodeList) SortByAired() {
+	sort.SliceStable(l, func(i, j int) bool {
+		a, b := l[i].FirstAired, l[j].FirstAired
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b.Time)
+	})
+}
+
+// SortBySeasonEpisode sorts the list in place by season and then episode
+// number.  Specials in season 0 come first.
+func (l EpisodeList) SortBySeasonEpisode() {
+	sort.SliceStable(l, func(i, j int) bool {
+		return seasonEpisodeLess(&l[i], &l[j])
+	})
+}
+
+// seasonEpisodeLess orders episodes by season and then episode number.
+func seasonEpisodeLess(a, b *Episode) bool {
+	if a.SeasonNumber != b.SeasonNumber {
+		return a.SeasonNumber < b.SeasonNumber
+	}
+	return a.EpisodeNumber < b.EpisodeNumber
+}
+
+// SeasonSummary holds statistics about the episodes in a season.
+type SeasonSummary struct {
+	Episodes int
+
+	// AverageRating is the mean rating of the episodes that have one or 0 if
+	// none do.
+	AverageRating float64
+
+	// FirstAired and LastAired are the earliest and latest episode air
+	// dates.  They are zero if no episode has an air date.
+	FirstAired time.Time
+	LastAired  time.Time
+}
+
+// SeasonSummary computes statistics for a season from the episodes in the
+// list.  A season with no episodes gives a zero SeasonSummary.
+func (l EpisodeList) SeasonSummary(season int) SeasonSummary {
+	var summary SeasonSummary
+	var total float64
+	rated := 0
+	for _, ep := range l {
+		if ep.SeasonNumber != season {
+			continue
+		}
+		summary.Episodes++
+
+		if ep.Rating.Valid {
+			total += ep.Rating.Value
+			rated++
+		}
+
+		if aired := ep.FirstAired.Time; !aired.IsZero() {
+			if summary.FirstAired.IsZero() || aired.Before(summary.FirstAired) {
+				summary.FirstAired = aired
+			}
+			if aired.After(summary.LastAired) {
+				summary.LastAired = aired
+			}
+		}
+	}
+
+	if rated > 0 {
+		summary.AverageRating = total / float64(rated)
+	}
+	return summary
+}
@@ -0,0 +1,341 @@
+package tvdb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// episodeIDs returns the IDs of episodes in order.
+func episodeIDs(episodes []*Episode) []int {
+	ids := []int{}
+	for _, ep := range episodes {
+		ids = append(ids, ep.ID)
+	}
+	return ids
+}
+
+func TestDVDSeasons(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, DVDSeason: NullInt(1), DVDEpisodeNumber: "2.0"},
+		{ID: 2, DVDSeason: NullInt(1), DVDEpisodeNumber: ""},
+		{ID: 3, DVDSeason: NullInt(1), DVDEpisodeNumber: "1.0"},
+		{ID: 4, DVDSeason: NullInt(2), DVDEpisodeNumber: "1.0"},
+		{ID: 5, DVDSeason: NulInt},
+	}
+
+	seasons := episodes.DVDSeasons()
+	if len(seasons) != 2 {
+		t.Errorf("Expected '2' seasons got '%d'", len(seasons))
+	}
+
+	want := map[int][]int{
+		1: {3, 1, 2},
+		2: {4},
+	}
+	for season, ids := range want {
+		if got := episodeIDs(seasons[season]); !reflect.DeepEqual(got, ids) {
+			t.Errorf("DVD season '%d' expected '%v' got '%v'", season, ids, got)
+		}
+	}
+}
+
+func TestSeasons(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, SeasonNumber: 1, EpisodeNumber: 2, DVDSeason: NullInt(1), DVDEpisodeNumber: "1.0", AbsoluteNumber: NullInt(2)},
+		{ID: 2, SeasonNumber: 1, EpisodeNumber: 1, DVDSeason: NullInt(1), DVDEpisodeNumber: "2.0", AbsoluteNumber: NullInt(1)},
+		{ID: 3, SeasonNumber: 2, EpisodeNumber: 1, DVDSeason: NullInt(1), DVDEpisodeNumber: "3.0", AbsoluteNumber: NullInt(3)},
+		{ID: 4, SeasonNumber: 0, EpisodeNumber: 1},
+	}
+
+	tests := map[Order]map[int][]int{
+		OrderDefault:  {0: {4}, 1: {2, 1}, 2: {3}},
+		OrderDVD:      {1: {1, 2, 3}},
+		OrderAbsolute: {1: {2, 1, 3}},
+	}
+	for order, want := range tests {
+		seasons := episodes.Seasons(order)
+		got := map[int][]int{}
+		for season, eps := range seasons {
+			got[season] = episodeIDs(eps)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Order '%s' expected '%v' got '%v'", order, want, got)
+		}
+	}
+}
+
+func TestByAbsoluteNumber(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, AbsoluteNumber: NulInt},
+		{ID: 2, AbsoluteNumber: NullInt(1)},
+		{ID: 3, AbsoluteNumber: NullInt(2)},
+	}
+
+	if ep, ok := episodes.ByAbsoluteNumber(2); !ok || ep.ID != 3 {
+		t.Errorf("Expected episode '3' got '%v' (%v)", ep, ok)
+	}
+	if _, ok := episodes.ByAbsoluteNumber(0); ok {
+		t.Error("Episodes without an absolute number should not match")
+	}
+}
+
+func TestEpisode(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, SeasonNumber: 1, EpisodeNumber: 1},
+		{ID: 2, SeasonNumber: 1, EpisodeNumber: 2},
+		{ID: 3, SeasonNumber: 2, EpisodeNumber: 1},
+	}
+
+	if ep, ok := episodes.Episode(2, 1); !ok || ep.ID != 3 {
+		t.Errorf("Expected episode '3' got '%v' (%v)", ep, ok)
+	}
+	if _, ok := episodes.Episode(2, 2); ok {
+		t.Error("Expected no episode for a missing episode number")
+	}
+}
+
+func TestNumberOfSeasons(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, SeasonNumber: 0},
+		{ID: 2, SeasonNumber: 1},
+		{ID: 3, SeasonNumber: 1},
+		{ID: 4, SeasonNumber: 2},
+	}
+
+	if n := episodes.NumberOfSeasons(false); n != 2 {
+		t.Errorf("Expected '2' seasons got '%d'", n)
+	}
+	if n := episodes.NumberOfSeasons(true); n != 3 {
+		t.Errorf("Expected '3' seasons with specials got '%d'", n)
+	}
+	if n := episodes.NumberOfEpisodes(); n != 4 {
+		t.Errorf("Expected '4' episodes got '%d'", n)
+	}
+	if n := (EpisodeList{}).NumberOfSeasons(true); n != 0 {
+		t.Errorf("Expected '0' seasons for an empty list got '%d'", n)
+	}
+}
+
+func TestSortedSeasons(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, SeasonNumber: 2, EpisodeNumber: 2},
+		{ID: 2, SeasonNumber: 1, EpisodeNumber: 1},
+		{ID: 3, SeasonNumber: 0, EpisodeNumber: 1},
+		{ID: 4, SeasonNumber: 2, EpisodeNumber: 1},
+	}
+
+	if got, want := episodes.SortedSeasons(), []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected seasons '%v' got '%v'", want, got)
+	}
+	if got, want := episodeIDs(episodes.EpisodesInSeason(2)), []int{4, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected episodes '%v' got '%v'", want, got)
+	}
+	if got := episodes.EpisodesInSeason(3); len(got) != 0 {
+		t.Errorf("Expected no episodes for a missing season got '%v'", episodeIDs(got))
+	}
+}
+
+func TestSeasonList(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, SeasonID: 20, SeasonNumber: 2, EpisodeNumber: 2},
+		{ID: 2, SeasonID: 10, SeasonNumber: 1, EpisodeNumber: 1},
+		{ID: 3, SeasonID: 5, SeasonNumber: 0, EpisodeNumber: 1},
+		{ID: 4, SeasonID: 20, SeasonNumber: 2, EpisodeNumber: 1},
+	}
+
+	seasons := episodes.SeasonList()
+	if len(seasons) != 3 {
+		t.Fatalf("Expected '3' seasons got '%d'", len(seasons))
+	}
+	want := []struct {
+		number, id int
+		episodes   []int
+	}{
+		{0, 5, []int{3}},
+		{1, 10, []int{2}},
+		{2, 20, []int{4, 1}},
+	}
+	for i, w := range want {
+		got := seasons[i]
+		if got.Number != w.number || got.SeasonID != w.id || !reflect.DeepEqual(episodeIDs(got.Episodes), w.episodes) {
+			t.Errorf("Expected season '%d' ID '%d' episodes '%v' got '%d' ID '%d' episodes '%v'",
+				w.number, w.id, w.episodes, got.Number, got.SeasonID, episodeIDs(got.Episodes))
+		}
+	}
+}
+
+func TestNextAiredEpisode(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, FirstAired: Date(1990, time.January, 14)},
+		{ID: 2, FirstAired: Date(1990, time.January, 28)},
+		{ID: 3},
+		{ID: 4, FirstAired: Date(1990, time.January, 21)},
+	}
+
+	if ep, ok := episodes.NextAiredEpisode(time.Date(1990, time.January, 14, 0, 0, 0, 0, time.UTC)); !ok || ep.ID != 4 {
+		t.Errorf("Expected episode '4' got '%v' (%v)", ep, ok)
+	}
+	if _, ok := episodes.NextAiredEpisode(time.Date(1990, time.January, 28, 0, 0, 0, 0, time.UTC)); ok {
+		t.Error("Expected no episode after the last air date")
+	}
+}
+
+func TestLatestAiredEpisode(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, FirstAired: Date(1990, time.January, 14)},
+		{ID: 2, FirstAired: Date(1990, time.January, 28)},
+		{ID: 3},
+		{ID: 4, FirstAired: Date(1990, time.January, 21)},
+	}
+
+	if ep, ok := episodes.LatestAiredEpisode(time.Date(1990, time.January, 25, 0, 0, 0, 0, time.UTC)); !ok || ep.ID != 4 {
+		t.Errorf("Expected episode '4' got '%v' (%v)", ep, ok)
+	}
+	if _, ok := episodes.LatestAiredEpisode(time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)); ok {
+		t.Error("Expected no episode before the first air date")
+	}
+}
+
+func TestRegularSeasonsAndSpecials(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, SeasonNumber: 2, EpisodeNumber: 1},
+		{ID: 2, SeasonNumber: 0, EpisodeNumber: 2},
+		{ID: 3, SeasonNumber: 1, EpisodeNumber: 1},
+		{ID: 4, SeasonNumber: 0, EpisodeNumber: 1},
+	}
+
+	if got, want := episodes.RegularSeasons(), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected seasons '%v' got '%v'", want, got)
+	}
+	if got, want := episodeIDs(episodes.Specials()), []int{4, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected specials '%v' got '%v'", want, got)
+	}
+
+	noSpecials := EpisodeList{{ID: 1, SeasonNumber: 1}}
+	if got, want := noSpecials.RegularSeasons(), []int{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected seasons '%v' got '%v'", want, got)
+	}
+}
+
+// listIDs returns the IDs of the episodes in a list in order.
+func listIDs(l EpisodeList) []int {
+	ids := []int{}
+	for _, ep := range l {
+		ids = append(ids, ep.ID)
+	}
+	return ids
+}
+
+func TestMerge(t *testing.T) {
+	cached := EpisodeList{
+		{ID: 1, EpisodeName: "Pilot"},
+		{ID: 2, EpisodeName: "TBA"},
+	}
+	fresh := EpisodeList{
+		{ID: 2, EpisodeName: "The Second One"},
+		{ID: 3, EpisodeName: "TBA"},
+	}
+
+	merged := cached.Merge(fresh)
+	if got, want := episodeIDs(merged.Sorted()), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected episodes '%v' got '%v'", want, got)
+	}
+	if merged[1].EpisodeName != "The Second One" {
+		t.Errorf("Expected 'The Second One' got '%s'", merged[1].EpisodeName)
+	}
+	if cached[1].EpisodeName != "TBA" {
+		t.Errorf("Expected the original list to be unchanged got '%s'", cached[1].EpisodeName)
+	}
+
+	if again := merged.Merge(fresh); !reflect.DeepEqual(again, merged) {
+		t.Errorf("Expected merging again to change nothing got '%v'", episodeIDs(again.Sorted()))
+	}
+}
+
+func TestSortByAired(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1},
+		{ID: 2, FirstAired: Date(1990, time.January, 28)},
+		{ID: 3, FirstAired: Date(1990, time.January, 14)},
+		{ID: 4},
+		{ID: 5, FirstAired: Date(1990, time.January, 14)},
+	}
+
+	episodes.SortByAired()
+	if got, want := listIDs(episodes), []int{3, 5, 2, 1, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected order '%v' got '%v'", want, got)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, SeasonNumber: 2, EpisodeNumber: 1},
+		{ID: 2, SeasonNumber: 1, EpisodeNumber: 2},
+		{ID: 3, SeasonNumber: 0, EpisodeNumber: 1},
+		{ID: 4, SeasonNumber: 1, EpisodeNumber: 1},
+	}
+
+	if got, want := episodeIDs(episodes.Sorted()), []int{3, 4, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected order '%v' got '%v'", want, got)
+	}
+	if got, want := listIDs(episodes), []int{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the list to be unchanged '%v' got '%v'", want, got)
+	}
+	if got := EpisodeList(nil).Sorted(); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice got '%#v'", got)
+	}
+}
+
+func TestSortBySeasonEpisode(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, SeasonNumber: 2, EpisodeNumber: 1},
+		{ID: 2, SeasonNumber: 1, EpisodeNumber: 2},
+		{ID: 3, SeasonNumber: 0, EpisodeNumber: 1},
+		{ID: 4, SeasonNumber: 1, EpisodeNumber: 1},
+	}
+
+	episodes.SortBySeasonEpisode()
+	if got, want := listIDs(episodes), []int{3, 4, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected order '%v' got '%v'", want, got)
+	}
+}
+
+func TestFilterAiredBetween(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, FirstAired: Date(1990, time.January, 14)},
+		{ID: 2, FirstAired: Date(1990, time.January, 28)},
+		{ID: 3},
+		{ID: 4, FirstAired: Date(1990, time.January, 21)},
+		{ID: 5, FirstAired: Date(1990, time.February, 4)},
+	}
+
+	start := time.Date(1990, time.January, 21, 0, 0, 0, 0, time.UTC)
+	end := time.Date(1990, time.January, 28, 0, 0, 0, 0, time.UTC)
+	if got, want := episodeIDs(episodes.FilterAiredBetween(start, end)), []int{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected episodes '%v' got '%v'", want, got)
+	}
+}
+
+func TestSeasonSummary(t *testing.T) {
+	episodes := EpisodeList{
+		{ID: 1, SeasonNumber: 1, Rating: NullFloat64(7.0), FirstAired: Date(1990, time.January, 21)},
+		{ID: 2, SeasonNumber: 1, Rating: NullFloat64(8.0), FirstAired: Date(1990, time.January, 14)},
+		{ID: 3, SeasonNumber: 1, Rating: NulFloat64},
+		{ID: 4, SeasonNumber: 2, Rating: NullFloat64(9.0), FirstAired: Date(1990, time.October, 11)},
+	}
+
+	want := SeasonSummary{
+		Episodes:      3,
+		AverageRating: 7.5,
+		FirstAired:    time.Date(1990, time.January, 14, 0, 0, 0, 0, time.UTC),
+		LastAired:     time.Date(1990, time.January, 21, 0, 0, 0, 0, time.UTC),
+	}
+	if got := episodes.SeasonSummary(1); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected summary '%+v' got '%+v'", want, got)
+	}
+
+	if got := episodes.SeasonSummary(3); !reflect.DeepEqual(got, SeasonSummary{}) {
+		t.Errorf("Expected an empty summary got '%+v'", got)
+	}
+}
@@ -1,14 +1,15 @@
 package tvdb_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/nemith/tvdb"
 )
 
-func ExampleSearch() {
+func ExampleClient_SearchSeries() {
 	t := tvdb.NewClient("90D7DF3AE9E4841E")
-	res, err := t.SearchSeries("The Simpsons", "en")
+	res, err := t.SearchSeries(context.Background(), "The Simpsons", "en")
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,188 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// imageURL returns the absolute URL of an image path relative to the banners
+// directory on base, or an empty string if path is empty.
+func imageURL(base *url.URL, path string) string {
+	if path == "" {
+		return ""
+	}
+	u := *base
+	u.Path = fmt.Sprintf("%s/banners/%s", strings.TrimSuffix(u.Path, "/"), strings.TrimPrefix(path, "/"))
+	u.RawQuery = ""
+	return u.String()
+}
+
+// ImageURL returns the absolute URL on the client's BaseURL for an image path
+// such as Series.BannerPath or Episode.BannerFilename.  An empty string is
+// returned for an empty path.  A nil client uses thetvdb.com.
+func (c *Client) ImageURL(path string) string {
+	return imageURL(c.baseURL(), path)
+}
+
+// DownloadImage fetches an image by its path relative to the banners directory
+// (e.g. Series.PostersPath) and returns its contents along with the content
+// type detected from them.
+func (c *Client) DownloadImage(ctx context.Context, path string) ([]byte, string, error) {
+	ctx = withEndpoint(ctx, "DownloadImage")
+	if path == "" {
+		return nil, "", errors.New("tvdb: empty image path")
+	}
+
+	resp, err := c.get(ctx, c.ImageURL(path))
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, "", ctxErr
+		}
+		return nil, "", err
+	}
+	return data, http.DetectContentType(data), nil
+}
+
+// DownloadImageTo streams an image by its path relative to the banners
+// directory to the file dest, creating any missing directories.  The ETag of
+// the image is kept next to dest in dest+".etag" and when both files exist
+// the image is only downloaded again if it has changed on the server.
+func (c *Client) DownloadImageTo(ctx context.Context, path, dest string) error {
+	ctx = withEndpoint(ctx, "DownloadImage")
+	if path == "" {
+		return errors.New("tvdb: empty image path")
+	}
+
+	etagPath := dest + ".etag"
+	header := http.Header{}
+	if _, err := os.Stat(dest); err == nil {
+		if etag, err := ioutil.ReadFile(etagPath); err == nil && len(etag) > 0 {
+			header.Set("If-None-Match", string(etag))
+		}
+	}
+
+	resp, err := c.getWithHeader(ctx, c.ImageURL(path), header, http.StatusNotModified)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+
+	dir := filepath.Dir(dest)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a failed download never leaves a
+	// partial image at dest
+	tmp, err := ioutil.TempFile(dir, ".tvdb-image-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// Temporary files are only readable by their owner
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return err
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return ioutil.WriteFile(etagPath, []byte(etag), 0644)
+	}
+	// Don't let a stale ETag match the new image
+	if err := os.Remove(etagPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// BannerURL returns the absolute URL of the series banner on c's BaseURL or
+// an empty string if the series has no banner.  A nil c uses thetvdb.com.
+func (s *SeriesSummary) BannerURL(c *Client) string {
+	return c.ImageURL(s.BannerPath)
+}
+
+// BannerURL returns the absolute URL of the series banner on c's BaseURL or
+// an empty string if the series has no banner.  A nil c uses thetvdb.com.
+func (s *Series) BannerURL(c *Client) string {
+	return c.ImageURL(s.BannerPath)
+}
+
+// PosterURL returns the absolute URL of the series poster on c's BaseURL or
+// an empty string if the series has no poster.  A nil c uses thetvdb.com.
+func (s *Series) PosterURL(c *Client) string {
+	return c.ImageURL(s.PostersPath)
+}
+
+// FanartURL returns the absolute URL of the series fanart on c's BaseURL or
+// an empty string if the series has no fanart.  A nil c uses thetvdb.com.
+func (s *Series) FanartURL(c *Client) string {
+	return c.ImageURL(s.FanartPath)
+}
+
+// SeasonPosterURL returns the absolute URL on c's BaseURL of the highest
+// rated poster for a season from the series' banners.  The series poster is
+// used when there is no poster for the season.  A nil c uses thetvdb.com.
+func (s *Series) SeasonPosterURL(c *Client, banners BannerList, season int) string {
+	if b, ok := banners.SeasonPoster(season); ok {
+		return b.URL(c)
+	}
+	return s.PosterURL(c)
+}
+
+// URL returns the absolute URL of the banner on c's BaseURL.  A nil c uses
+// thetvdb.com.
+func (b *Banner) URL(c *Client) string {
+	return c.ImageURL(b.BannerPath)
+}
+
+// ThumbnailURL returns the absolute URL of the episode thumbnail on c's
+// BaseURL or an empty string if the episode has no thumbnail.  A nil c uses
+// thetvdb.com.
+func (e *Episode) ThumbnailURL(c *Client) string {
+	return c.ImageURL(e.BannerFilename)
+}
+
+// HasImage reports whether the episode has a thumbnail.  Thumbnails flagged
+// with problems such as ImgFlagTooSmall still count.
+func (e *Episode) HasImage() bool {
+	return e.EpImgFlag != ImgFlagNone && e.BannerFilename != ""
+}
+
+// ThumbSize returns the width and height of the episode thumbnail in pixels.
+// ok is false and both are 0 if either dimension is unknown.
+func (e *Episode) ThumbSize() (width, height int, ok bool) {
+	if !e.ThumbWidth.Valid || !e.ThumbHeight.Valid {
+		return 0, 0, false
+	}
+	return e.ThumbWidth.Value, e.ThumbHeight.Value, true
+}
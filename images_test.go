@@ -0,0 +1,170 @@
+package tvdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageURL(t *testing.T) {
+	client := NewClient(apiKey)
+	client.BaseURL, _ = url.Parse("http://mirror.example.com/tvdb/")
+
+	tests := []struct {
+		got, want string
+	}{
+		{client.ImageURL("graphical/71663-g13.jpg"), "http://mirror.example.com/tvdb/banners/graphical/71663-g13.jpg"},
+		{client.ImageURL(""), ""},
+		{(&SeriesSummary{BannerPath: "graphical/71663-g13.jpg"}).BannerURL(client), "http://mirror.example.com/tvdb/banners/graphical/71663-g13.jpg"},
+		{(&Series{BannerPath: "graphical/71663-g13.jpg"}).BannerURL(client), "http://mirror.example.com/tvdb/banners/graphical/71663-g13.jpg"},
+		{(&Series{PostersPath: "posters/71663-1.jpg"}).PosterURL(client), "http://mirror.example.com/tvdb/banners/posters/71663-1.jpg"},
+		{(&Series{FanartPath: "fanart/original/71663-31.jpg"}).FanartURL(client), "http://mirror.example.com/tvdb/banners/fanart/original/71663-31.jpg"},
+		{(&Series{}).FanartURL(client), ""},
+		{(&Episode{BannerFilename: "episodes/71663/55452.jpg"}).ThumbnailURL(client), "http://mirror.example.com/tvdb/banners/episodes/71663/55452.jpg"},
+		{(&Banner{BannerPath: "seasons/71663-1.jpg"}).URL(client), "http://mirror.example.com/tvdb/banners/seasons/71663-1.jpg"},
+		{(&Series{PostersPath: "posters/71663-1.jpg"}).PosterURL(nil), "https://thetvdb.com/banners/posters/71663-1.jpg"},
+	}
+
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("Expected '%s' got '%s'", test.want, test.got)
+		}
+	}
+}
+
+func TestDownloadImage(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	// Smallest valid GIF
+	gif := []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;")
+	mux.HandleFunc("/banners/posters/71663-1.jpg", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(gif)
+	})
+
+	data, contentType, err := client.DownloadImage(context.Background(), "posters/71663-1.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, gif) {
+		t.Errorf("Expected '%q' got '%q'", gif, data)
+	}
+	if contentType != "image/gif" {
+		t.Errorf("Expected content type 'image/gif' got '%s'", contentType)
+	}
+
+	if _, _, err := client.DownloadImage(context.Background(), "posters/missing.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestThumbSize(t *testing.T) {
+	ep := &Episode{ThumbWidth: NullInt(400), ThumbHeight: NullInt(300)}
+	if w, h, ok := ep.ThumbSize(); !ok || w != 400 || h != 300 {
+		t.Errorf("Expected '400x300' got '%dx%d' (%v)", w, h, ok)
+	}
+
+	ep = &Episode{ThumbWidth: NullInt(400), ThumbHeight: NulInt}
+	if w, h, ok := ep.ThumbSize(); ok || w != 0 || h != 0 {
+		t.Errorf("Expected no size got '%dx%d' (%v)", w, h, ok)
+	}
+}
+
+func TestDownloadImageTo(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "tvdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	gif := []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;")
+	downloads := 0
+	mux.HandleFunc("/banners/posters/71663-1.jpg", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(gif)
+	})
+
+	observer := &recordingObserver{}
+	client.Observer = observer
+
+	dest := filepath.Join(dir, "posters", "71663-1.jpg")
+	for i := 0; i < 2; i++ {
+		if err := client.DownloadImageTo(context.Background(), "posters/71663-1.jpg", dest); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if downloads != 1 {
+		t.Errorf("Expected '1' download got '%d'", downloads)
+	}
+	// The unchanged image isn't a failed request
+	if len(observer.errs) != 2 || observer.errs[0] != nil || observer.errs[1] != nil {
+		t.Errorf("Expected '2' successful requests got '%v'", observer.errs)
+	}
+
+	data, err := ioutil.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, gif) {
+		t.Errorf("Expected '%q' got '%q'", gif, data)
+	}
+
+	// A missing image is downloaded again even with a matching ETag
+	os.Remove(dest)
+	if err := client.DownloadImageTo(context.Background(), "posters/71663-1.jpg", dest); err != nil {
+		t.Fatal(err)
+	}
+	if downloads != 2 {
+		t.Errorf("Expected '2' downloads got '%d'", downloads)
+	}
+
+	if err := client.DownloadImageTo(context.Background(), "posters/missing.jpg", filepath.Join(dir, "missing.jpg")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestSeasonPosterURL(t *testing.T) {
+	series := &Series{PostersPath: "posters/71663-1.jpg"}
+	banners := BannerList{
+		{ID: 3285, BannerPath: "seasons/71663-1.jpg", BannerType: BannerSeason, BannerType2: "season", Season: NullInt(1)},
+	}
+
+	if got, want := series.SeasonPosterURL(nil, banners, 1), "https://thetvdb.com/banners/seasons/71663-1.jpg"; got != want {
+		t.Errorf("Expected '%s' got '%s'", want, got)
+	}
+	if got, want := series.SeasonPosterURL(nil, banners, 2), "https://thetvdb.com/banners/posters/71663-1.jpg"; got != want {
+		t.Errorf("Expected the series poster '%s' got '%s'", want, got)
+	}
+}
+
+func TestHasImage(t *testing.T) {
+	tests := []struct {
+		ep   Episode
+		want bool
+	}{
+		{Episode{EpImgFlag: ImgFlag4x3, BannerFilename: "episodes/71663/55452.jpg"}, true},
+		{Episode{EpImgFlag: ImgFlagTooSmall, BannerFilename: "episodes/71663/55452.jpg"}, true},
+		{Episode{EpImgFlag: ImgFlagNone, BannerFilename: "episodes/71663/55452.jpg"}, false},
+		{Episode{EpImgFlag: ImgFlag16x9}, false},
+	}
+
+	for _, test := range tests {
+		if got := test.ep.HasImage(); got != test.want {
+			t.Errorf("Flag '%s' with file '%s' expected '%t' got '%t'", test.ep.EpImgFlag, test.ep.BannerFilename, test.want, got)
+		}
+	}
+}
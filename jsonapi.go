@@ -0,0 +1,417 @@
+package tvdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultJSONBaseURL returns the URL of TheTVDB JSON API.
+func defaultJSONBaseURL() *url.URL {
+	return &url.URL{
+		Scheme: "https",
+		Host:   "api.thetvdb.com",
+	}
+}
+
+// JSONClient is the base of all calls to the JSON API at api.thetvdb.com
+// which replaces the deprecated XML API used by Client.  Requests are
+// authenticated with a token obtained by logging in with the API key.  This
+// happens automatically on the first request.  Tokens expire after 24 hours
+// so a rejected token is refreshed, or failing that a new one is requested,
+// and the request retried.  Results are mapped into the same types returned
+// by Client.  A JSONClient is safe for concurrent use.
+type JSONClient struct {
+	APIKey string
+
+	// BaseURL is the root that all API URLs are built from.  If nil the
+	// canonical api.thetvdb.com host is used.
+	BaseURL *url.URL
+
+	// HTTPClient is used to make all requests.  If nil http.DefaultClient is
+	// used.
+	HTTPClient Doer
+
+	// UserAgent is sent as the User-Agent header of every request.  If empty
+	// DefaultUserAgent is used.
+	UserAgent string
+
+	mu    sync.Mutex
+	token string
+
+	// authMu serializes logins and refreshes so concurrent requests with the
+	// same expired token only renew it once.
+	authMu sync.Mutex
+}
+
+// NewJSONClient returns a new TVDB JSON API instance.
+func NewJSONClient(apiKey string) *JSONClient {
+	return &JSONClient{
+		APIKey:     apiKey,
+		BaseURL:    defaultJSONBaseURL(),
+		HTTPClient: &http.Client{Timeout: DefaultTimeout, Transport: DefaultTransport()},
+	}
+}
+
+// Close forgets the current token and closes the idle connections of the
+// HTTPClient.  The client logs in again if it is used afterwards.
+func (c *JSONClient) Close() error {
+	c.setToken("")
+	if hc, ok := c.HTTPClient.(interface{ CloseIdleConnections() }); ok {
+		hc.CloseIdleConnections()
+	}
+	return nil
+}
+
+// httpClient returns the configured HTTPClient or http.DefaultClient if none
+// has been set.
+func (c *JSONClient) httpClient() Doer {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+// apiURL returns the full url for an API path such as "/series/71663".
+func (c *JSONClient) apiURL(path string, query url.Values) string {
+	base := c.BaseURL
+	if base == nil {
+		base = defaultJSONBaseURL()
+	}
+	u := *base
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// currentToken returns the token from the last successful login.
+func (c *JSONClient) currentToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// setToken replaces the token used for requests.
+func (c *JSONClient) setToken(token string) {
+	c.mu.Lock()
+	c.token = token
+	c.mu.Unlock()
+}
+
+// Login authenticates with the API key and stores the token used by later
+// requests.  It does not need to be called explicitly.
+func (c *JSONClient) Login(ctx context.Context) error {
+	body, err := json.Marshal(struct {
+		APIKey string `json:"apikey"`
+	}{c.APIKey})
+	if err != nil {
+		return err
+	}
+
+	resp := struct {
+		Token string `json:"token"`
+	}{}
+	if err := c.send(ctx, "POST", c.apiURL("/login", nil), "", "", bytes.NewReader(body), &resp); err != nil {
+		return err
+	}
+
+	c.setToken(resp.Token)
+	return nil
+}
+
+// reauthenticate replaces a stale token, first by refreshing it and then by
+// logging in again if the refresh is rejected.  If another request has
+// already replaced stale nothing is done.
+func (c *JSONClient) reauthenticate(ctx context.Context, stale string) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.currentToken() != stale {
+		return nil
+	}
+
+	if stale != "" {
+		resp := struct {
+			Token string `json:"token"`
+		}{}
+		if err := c.send(ctx, "GET", c.apiURL("/refresh_token", nil), stale, "", nil, &resp); err == nil {
+			c.setToken(resp.Token)
+			return nil
+		}
+	}
+	return c.Login(ctx)
+}
+
+// send makes a single request and decodes the JSON response into v.
+func (c *JSONClient) send(ctx context.Context, method, url, token, lang string, body io.Reader, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent(c.UserAgent))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if lang != "" {
+		req.Header.Set("Accept-Language", lang)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return &HTTPError{StatusCode: resp.StatusCode, URL: url}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+// get makes an authenticated GET request logging in first if there is no
+// token yet.  A 401 response means the token has expired so it is renewed and
+// the request retried once.
+func (c *JSONClient) get(ctx context.Context, path string, query url.Values, lang string, v interface{}) error {
+	token := c.currentToken()
+	if token == "" {
+		if err := c.reauthenticate(ctx, token); err != nil {
+			return err
+		}
+		token = c.currentToken()
+	}
+
+	u := c.apiURL(path, query)
+	err := c.send(ctx, "GET", u, token, lang, nil, v)
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == http.StatusUnauthorized {
+		if err := c.reauthenticate(ctx, token); err != nil {
+			return err
+		}
+		return c.send(ctx, "GET", u, c.currentToken(), lang, nil, v)
+	}
+	return err
+}
+
+// jsonSeries is a series as returned by the JSON API.
+type jsonSeries struct {
+	ID              int      `json:"id"`
+	SeriesName      string   `json:"seriesName"`
+	Aliases         []string `json:"aliases"`
+	Banner          string   `json:"banner"`
+	Status          string   `json:"status"`
+	FirstAired      string   `json:"firstAired"`
+	Network         string   `json:"network"`
+	Runtime         string   `json:"runtime"`
+	Genre           []string `json:"genre"`
+	Overview        string   `json:"overview"`
+	LastUpdated     int64    `json:"lastUpdated"`
+	AirsDayOfWeek   string   `json:"airsDayOfWeek"`
+	AirsTime        string   `json:"airsTime"`
+	Rating          string   `json:"rating"`
+	IMDBID          string   `json:"imdbId"`
+	Zap2itID        string   `json:"zap2itId"`
+	Added           string   `json:"added"`
+	AddedBy         *int     `json:"addedBy"`
+	SiteRating      *float64 `json:"siteRating"`
+	SiteRatingCount *int     `json:"siteRatingCount"`
+}
+
+func (s *jsonSeries) series() *Series {
+	return &Series{
+		ID:            s.ID,
+		Name:          s.SeriesName,
+		Aliases:       pipeList(s.Aliases),
+		BannerPath:    s.Banner,
+		Overview:      s.Overview,
+		FirstAired:    jsonDate(s.FirstAired),
+		IMDBID:        s.IMDBID,
+		Zap2itID:      s.Zap2itID,
+		Network:       s.Network,
+		AirsDayOfWeek: s.AirsDayOfWeek,
+		AirsTime:      s.AirsTime,
+		ContentRating: s.Rating,
+		Genre:         pipeList(s.Genre),
+		Rating:        jsonNullFloat64(s.SiteRating),
+		RatingCount:   jsonNullInt(s.SiteRatingCount),
+		Runtime:       jsonAtoi(s.Runtime),
+		Status:        s.Status,
+		Added:         jsonDateTime(s.Added),
+		AddedBy:       jsonNullInt(s.AddedBy),
+		LastUpdated:   jsonUnixTime(s.LastUpdated),
+	}
+}
+
+// jsonEpisode is an episode as returned by the JSON API.
+type jsonEpisode struct {
+	ID                 int      `json:"id"`
+	AiredSeason        int      `json:"airedSeason"`
+	AiredSeasonID      int      `json:"airedSeasonID"`
+	AiredEpisodeNumber int      `json:"airedEpisodeNumber"`
+	EpisodeName        string   `json:"episodeName"`
+	FirstAired         string   `json:"firstAired"`
+	GuestStars         []string `json:"guestStars"`
+	Directors          []string `json:"directors"`
+	Writers            []string `json:"writers"`
+	Overview           string   `json:"overview"`
+	Language           struct {
+		EpisodeName string `json:"episodeName"`
+	} `json:"language"`
+	ProductionCode   string   `json:"productionCode"`
+	LastUpdated      int64    `json:"lastUpdated"`
+	DVDSeason        *int     `json:"dvdSeason"`
+	DVDEpisodeNumber *float64 `json:"dvdEpisodeNumber"`
+	AbsoluteNumber   *int     `json:"absoluteNumber"`
+	Filename         string   `json:"filename"`
+	SeriesID         int      `json:"seriesId"`
+	ThumbAdded       string   `json:"thumbAdded"`
+	ThumbWidth       string   `json:"thumbWidth"`
+	ThumbHeight      string   `json:"thumbHeight"`
+	IMDBID           string   `json:"imdbId"`
+	SiteRating       *float64 `json:"siteRating"`
+	SiteRatingCount  *int     `json:"siteRatingCount"`
+}
+
+func (e *jsonEpisode) episode() Episode {
+	ep := Episode{
+		ID:             e.ID,
+		DVDSeason:      jsonNullInt(e.DVDSeason),
+		Director:       pipeList(e.Directors),
+		EpisodeName:    e.EpisodeName,
+		EpisodeNumber:  e.AiredEpisodeNumber,
+		FirstAired:     jsonDate(e.FirstAired),
+		GuestStars:     pipeList(e.GuestStars),
+		IMDBID:         e.IMDBID,
+		Language:       e.Language.EpisodeName,
+		Overview:       e.Overview,
+		ProductionCode: e.ProductionCode,
+		Rating:         jsonNullFloat64(e.SiteRating),
+		RatingCount:    jsonNullInt(e.SiteRatingCount),
+		SeasonNumber:   e.AiredSeason,
+		Writer:         pipeList(e.Writers),
+		AbsoluteNumber: jsonNullInt(e.AbsoluteNumber),
+		BannerFilename: e.Filename,
+		LastUpdated:    jsonUnixTime(e.LastUpdated),
+		SeasonID:       e.AiredSeasonID,
+		SeriesID:       e.SeriesID,
+		ThumbAdded:     jsonDateTime(e.ThumbAdded),
+		ThumbHeight:    jsonAtoi(e.ThumbHeight),
+		ThumbWidth:     jsonAtoi(e.ThumbWidth),
+	}
+	// Match the "1.0" formatting used by the XML API
+	if e.DVDEpisodeNumber != nil {
+		ep.DVDEpisodeNumber = strconv.FormatFloat(*e.DVDEpisodeNumber, 'f', 1, 64)
+	}
+	return ep
+}
+
+func jsonNullInt(i *int) nullInt {
+	if i == nil {
+		return NulInt
+	}
+	return NullInt(*i)
+}
+
+func jsonNullFloat64(f *float64) nullFloat64 {
+	if f == nil {
+		return NulFloat64
+	}
+	return NullFloat64(*f)
+}
+
+// jsonAtoi parses numbers the JSON API returns as strings.  Blank or invalid
+// values are not valid.
+func jsonAtoi(s string) nullInt {
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return NulInt
+	}
+	return NullInt(i)
+}
+
+func jsonDate(s string) date {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return date{}
+	}
+	return date{t}
+}
+
+func jsonDateTime(s string) dateTime {
+	t, err := time.Parse("2006-01-02 15:04:05", s)
+	if err != nil {
+		return NullDateTime
+	}
+	return dateTime{t}
+}
+
+// jsonUnixTime converts a Unix timestamp where 0, or a missing one, means
+// unset to the zero time like the XML API.
+func jsonUnixTime(sec int64) unixTime {
+	if sec == 0 {
+		return unixTime{}
+	}
+	return unixTime{time.Unix(sec, 0).UTC()}
+}
+
+// SeriesByID gets a single series' details from the TVDB series id.
+func (c *JSONClient) SeriesByID(ctx context.Context, id int, lang string) (*Series, error) {
+	resp := struct {
+		Data jsonSeries `json:"data"`
+	}{}
+	if err := c.get(ctx, fmt.Sprintf("/series/%d", id), nil, lang, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data.series(), nil
+}
+
+// EpisodesBySeries gets every episode of a series.  The API returns episodes
+// a page at a time so this may make several requests.
+func (c *JSONClient) EpisodesBySeries(ctx context.Context, id int, lang string) (EpisodeList, error) {
+	episodes := EpisodeList{}
+	for page := 1; ; {
+		resp := struct {
+			Links struct {
+				Next *int `json:"next"`
+			} `json:"links"`
+			Data []jsonEpisode `json:"data"`
+		}{}
+		query := url.Values{"page": []string{strconv.Itoa(page)}}
+		if err := c.get(ctx, fmt.Sprintf("/series/%d/episodes", id), query, lang, &resp); err != nil {
+			return nil, err
+		}
+
+		for i := range resp.Data {
+			episodes = append(episodes, resp.Data[i].episode())
+		}
+
+		if resp.Links.Next == nil {
+			return episodes, nil
+		}
+		// A next page that doesn't move forward would loop forever
+		if *resp.Links.Next <= page {
+			return nil, fmt.Errorf("tvdb: episode page '%d' links to page '%d'", page, *resp.Links.Next)
+		}
+		page = *resp.Links.Next
+	}
+}
@@ -0,0 +1,250 @@
+package tvdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// jsonServer is a fake JSON API that issues numbered tokens and only accepts
+// the most recent one.  Any token it has issued can be refreshed.
+type jsonServer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu        sync.Mutex
+	tokens    int
+	logins    int
+	refreshes int
+}
+
+// issue returns a new token invalidating all previous ones.
+func (js *jsonServer) issue(w http.ResponseWriter) {
+	js.tokens++
+	fmt.Fprintf(w, `{"token": "token-%d"}`, js.tokens)
+}
+
+// expire invalidates the current token.
+func (js *jsonServer) expire() {
+	js.mu.Lock()
+	js.tokens++
+	js.mu.Unlock()
+}
+
+func newJSONServer(t *testing.T) *jsonServer {
+	js := &jsonServer{t: t}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			APIKey string `json:"apikey"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.APIKey != apiKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		js.mu.Lock()
+		defer js.mu.Unlock()
+		js.logins++
+		js.issue(w)
+	})
+	mux.HandleFunc("/refresh_token", func(w http.ResponseWriter, r *http.Request) {
+		js.mu.Lock()
+		defer js.mu.Unlock()
+		var n int
+		if _, err := fmt.Sscanf(r.Header.Get("Authorization"), "Bearer token-%d", &n); err != nil || n > js.tokens {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		js.refreshes++
+		js.issue(w)
+	})
+	mux.HandleFunc("/series/71663", js.authorized(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/json/series_71663.json")
+	}))
+	mux.HandleFunc("/series/71663/episodes", js.authorized(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, fmt.Sprintf("testdata/json/series_71663_episodes_%s.json", r.FormValue("page")))
+	}))
+	// Series 1 links every page back to itself
+	mux.HandleFunc("/series/1/episodes", js.authorized(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"links": {"next": %s}, "data": []}`, r.FormValue("page"))
+	}))
+	js.Server = httptest.NewServer(mux)
+	return js
+}
+
+func (js *jsonServer) authorized(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		js.mu.Lock()
+		current := fmt.Sprintf("Bearer token-%d", js.tokens)
+		js.mu.Unlock()
+		if r.Header.Get("Authorization") != current {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if lang := r.Header.Get("Accept-Language"); lang != "en" {
+			js.t.Errorf("Expected Accept-Language 'en' got '%s'", lang)
+		}
+		h(w, r)
+	}
+}
+
+func setupJSON(t *testing.T) (*JSONClient, *jsonServer) {
+	js := newJSONServer(t)
+	client := NewJSONClient(apiKey)
+	client.BaseURL, _ = url.Parse(js.URL)
+	return client, js
+}
+
+func TestJSONSeriesByID(t *testing.T) {
+	client, js := setupJSON(t)
+	defer js.Close()
+
+	series, err := client.SeriesByID(context.Background(), 71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Series{
+		ID:            71663,
+		Name:          "The Simpsons",
+		Aliases:       pipeList{"Los Simpson"},
+		BannerPath:    "graphical/71663-g13.jpg",
+		Overview:      "Set in Springfield, the average American town, the show focuses on the antics and everyday adventures of the Simpson family.",
+		FirstAired:    Date(1989, time.December, 17),
+		IMDBID:        "tt0096697",
+		Zap2itID:      "EP00018693",
+		Network:       "FOX",
+		AirsDayOfWeek: "Sunday",
+		AirsTime:      "8:00 PM",
+		ContentRating: "TV-PG",
+		Genre:         pipeList{"Animation", "Comedy"},
+		Rating:        NullFloat64(9.0),
+		RatingCount:   NullInt(543),
+		Runtime:       NullInt(30),
+		Status:        "Continuing",
+		Added:         NullDateTime,
+		AddedBy:       NulInt,
+		LastUpdated:   unixTime{time.Date(2015, time.January, 30, 19, 1, 41, 0, time.UTC)},
+	}
+
+	if !reflect.DeepEqual(series, want) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(want, series))
+	}
+}
+
+func TestJSONEpisodesBySeries(t *testing.T) {
+	client, js := setupJSON(t)
+	defer js.Close()
+
+	// Start with a token that has since expired
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	js.expire()
+
+	episodes, err := client.EpisodesBySeries(context.Background(), 71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if js.logins != 1 || js.refreshes != 1 {
+		t.Errorf("Expected an expired token to be refreshed got '%d' logins and '%d' refreshes", js.logins, js.refreshes)
+	}
+
+	if len(episodes) != 2 {
+		t.Fatalf("Expected '2' episodes across pages got '%d'", len(episodes))
+	}
+
+	want := Episode{
+		ID:               55452,
+		DVDEpisodeNumber: "1.0",
+		DVDSeason:        NullInt(1),
+		Director:         pipeList{"David Silverman"},
+		EpisodeName:      "Simpsons Roasting on an Open Fire",
+		EpisodeNumber:    1,
+		FirstAired:       Date(1989, time.December, 17),
+		GuestStars:       pipeList{"Christopher Collins"},
+		Language:         "en",
+		Overview:         "When his Christmas bonus is cancelled, Homer becomes a department-store Santa.",
+		ProductionCode:   "7G08",
+		Rating:           NullFloat64(7.2),
+		RatingCount:      NullInt(12),
+		SeasonNumber:     1,
+		Writer:           pipeList{"Mimi Pond"},
+		AbsoluteNumber:   NullInt(1),
+		BannerFilename:   "episodes/71663/55452.jpg",
+		LastUpdated:      unixTime{time.Date(2011, time.May, 31, 2, 38, 5, 0, time.UTC)},
+		SeasonID:         2727,
+		SeriesID:         71663,
+		ThumbAdded:       NullDateTime,
+		ThumbHeight:      NullInt(300),
+		ThumbWidth:       NullInt(400),
+	}
+
+	if !reflect.DeepEqual(episodes[0], want) {
+		t.Errorf("Episode 0 does not match.  \n%s", pretty.Compare(want, episodes[0]))
+	}
+	if episodes[1].DVDSeason.Valid || episodes[1].DVDEpisodeNumber != "" {
+		t.Errorf("Expected no DVD numbering for episode '%d'", episodes[1].ID)
+	}
+}
+
+func TestJSONUnixTime(t *testing.T) {
+	if got := jsonUnixTime(0); !got.IsZero() {
+		t.Errorf("Expected the zero time for 0 got '%s'", got)
+	}
+	if got, want := jsonUnixTime(1422644501), time.Date(2015, time.January, 30, 19, 1, 41, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected '%s' got '%s'", want, got)
+	}
+
+	var s jsonSeries
+	if err := json.Unmarshal([]byte(`{"id": 1}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.series().LastUpdated; !got.IsZero() {
+		t.Errorf("Expected the zero time for a missing lastUpdated got '%s'", got)
+	}
+}
+
+func TestJSONEpisodesBySeriesPageLoop(t *testing.T) {
+	client, js := setupJSON(t)
+	defer js.Close()
+
+	if _, err := client.EpisodesBySeries(context.Background(), 1, "en"); err == nil {
+		t.Error("Expected an error for a next page that doesn't move forward")
+	}
+}
+
+func TestJSONConcurrentRefresh(t *testing.T) {
+	client, js := setupJSON(t)
+	defer js.Close()
+
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	js.expire()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.SeriesByID(context.Background(), 71663, "en"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if js.logins != 1 || js.refreshes != 1 {
+		t.Errorf("Expected a single refresh got '%d' logins and '%d' refreshes", js.logins, js.refreshes)
+	}
+}
@@ -0,0 +1,68 @@
+package tvdb
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+)
+
+// seriesDoc is a <Data> document with a series and any of its episodes such
+// as series/<id>/<lang>.xml or series/<id>/all/<lang>.xml.
+type seriesDoc struct {
+	XMLName  xml.Name `xml:"Data"`
+	Series   Series
+	Episodes EpisodeList `xml:"Episode"`
+}
+
+// seriesListDoc is a <Data> document of search results from GetSeries.php.
+type seriesListDoc struct {
+	XMLName xml.Name `xml:"Data"`
+	Series  SeriesList
+}
+
+// actorsDoc is the series/<id>/actors.xml document.
+type actorsDoc struct {
+	XMLName xml.Name `xml:"Actors"`
+	Actors  []Actor  `xml:"Actor"`
+}
+
+// bannersDoc is the series/<id>/banners.xml document.
+type bannersDoc struct {
+	XMLName xml.Name   `xml:"Banners"`
+	Banners BannerList `xml:"Banner"`
+}
+
+// ParseSeries parses the series from a series/<id>/<lang>.xml or
+// series/<id>/all/<lang>.xml document such as a saved copy of one from
+// TheTVDB.  ErrNotFound is returned if the document has no series.
+func ParseSeries(r io.Reader) (*Series, error) {
+	var doc seriesDoc
+	if err := decode(context.Background(), r, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Series.ID == 0 {
+		return nil, ErrNotFound
+	}
+	return &doc.Series, nil
+}
+
+// ParseEpisodeList parses the episodes from a <Data> document of episodes
+// such as series/<id>/all/<lang>.xml.  The list is empty if the document has
+// none.
+func ParseEpisodeList(r io.Reader) (EpisodeList, error) {
+	var doc seriesDoc
+	if err := decode(context.Background(), r, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Episodes, nil
+}
+
+// ParseSeriesList parses the series from a GetSeries.php search result
+// document in document order.
+func ParseSeriesList(r io.Reader) (SeriesList, error) {
+	var doc seriesListDoc
+	if err := decode(context.Background(), r, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Series, nil
+}
@@ -0,0 +1,71 @@
+package tvdb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestParse(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_all_en.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), handler)
+
+	wantSeries, wantEpisodes, err := client.SeriesAllByID(context.Background(), 71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open("testdata/series_71663_all_en.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	series, err := ParseSeries(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(wantSeries, series) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(wantSeries, series))
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	episodes, err := ParseEpisodeList(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(wantEpisodes, episodes) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(wantEpisodes, episodes))
+	}
+}
+
+func TestParseSeriesList(t *testing.T) {
+	f, err := os.Open("testdata/GetSeries.php?seriesname=The%20Simpsons")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	series, err := ParseSeriesList(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := series.IDs(), []int{71663, 153221}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected IDs '%v' got '%v'", want, got)
+	}
+}
+
+func TestParseSeriesNotFound(t *testing.T) {
+	if _, err := ParseSeries(strings.NewReader(`<?xml version="1.0" encoding="UTF-8" ?><Data></Data>`)); err != ErrNotFound {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+}
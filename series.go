@@ -0,0 +1,241 @@
+package tvdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SeriesList is a list of series summaries such as the results of
+// SearchSeries.
+type SeriesList []SeriesSummary
+
+// FindByName returns the first series whose name matches name ignoring case.
+func (l SeriesList) FindByName(name string) (*SeriesSummary, bool) {
+	name = strings.TrimSpace(name)
+	for i := range l {
+		if strings.EqualFold(l[i].Name, name) {
+			return &l[i], true
+		}
+	}
+	return nil, false
+}
+
+// IDs returns the ID of each series in list order.
+func (l SeriesList) IDs() []int {
+	ids := make([]int, len(l))
+	for i := range l {
+		ids[i] = l[i].ID
+	}
+	return ids
+}
+
+// FilterByName returns the series whose name contains substr ignoring case.
+func (l SeriesList) FilterByName(substr string) []*SeriesSummary {
+	substr = strings.ToLower(strings.TrimSpace(substr))
+	series := []*SeriesSummary{}
+	for i := range l {
+		if strings.Contains(strings.ToLower(l[i].Name), substr) {
+			series = append(series, &l[i])
+		}
+	}
+	return series
+}
+
+// RankedSeries is a search result with a Score from 0 to 1 of how closely its
+// name matches the search term.
+type RankedSeries struct {
+	SeriesSummary
+	Score float64
+}
+
+// Ranked returns the series ordered by how closely their names match term,
+// best first.  Exact matches ignoring case and punctuation score 1, followed
+// by names starting with term, names containing term, and finally the rest
+// by edit distance.  Series that score the same keep their order.
+func (l SeriesList) Ranked(term string) []RankedSeries {
+	ranked := make([]RankedSeries, len(l))
+	for i := range l {
+		ranked[i] = RankedSeries{SeriesSummary: l[i], Score: matchScore(term, l[i].Name)}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
+
+// matchScore scores how closely name matches term.  Each kind of match is
+// given its own band so an exact match always beats a prefix which always
+// beats a substring which beats any fuzzy match.
+func matchScore(term, name string) float64 {
+	t, n := []rune(normalizeTitle(term)), []rune(normalizeTitle(name))
+	ts, ns := string(t), string(n)
+	switch {
+	case ts == ns:
+		return 1
+	case len(t) == 0 || len(n) == 0:
+		return 0
+	case strings.HasPrefix(ns, ts):
+		return 0.75 + 0.25*float64(len(t))/float64(len(n))
+	case strings.Contains(ns, ts):
+		return 0.5 + 0.25*float64(len(t))/float64(len(n))
+	}
+
+	longest := len(t)
+	if len(n) > longest {
+		longest = len(n)
+	}
+	return 0.5 * (1 - float64(levenshtein(t, n))/float64(longest))
+}
+
+// normalizeTitle lower cases s and replaces runs of punctuation and spaces
+// with a single space.
+func normalizeTitle(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// levenshtein returns the number of single rune edits to turn a into b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// MatchesTitle reports whether title is the series' name or one of its
+// aliases ignoring case and surrounding space.
+func (s *SeriesSummary) MatchesTitle(title string) bool {
+	return matchesTitle(title, s.Name, s.Aliases)
+}
+
+// MatchesTitle reports whether title is the series' name or one of its
+// aliases ignoring case and surrounding space.
+func (s *Series) MatchesTitle(title string) bool {
+	return matchesTitle(title, s.Name, s.Aliases)
+}
+
+func matchesTitle(title, name string, aliases []string) bool {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return false
+	}
+	if strings.EqualFold(strings.TrimSpace(name), title) {
+		return true
+	}
+	for _, alias := range aliases {
+		if strings.EqualFold(strings.TrimSpace(alias), title) {
+			return true
+		}
+	}
+	return false
+}
+
+// contentRatings orders the US TV Parental Guidelines from least to most
+// restrictive.
+var contentRatings = map[string]int{
+	"TV-Y":     0,
+	"TV-Y7":    1,
+	"TV-Y7-FV": 1,
+	"TV-G":     2,
+	"TV-PG":    3,
+	"TV-14":    4,
+	"TV-MA":    5,
+}
+
+// ContentRatingAtMost reports whether the series' US TV content rating, such
+// as "TV-14", is at or below maxRating.  It is false if either rating is
+// unknown so filters fail closed.
+func (s *Series) ContentRatingAtMost(maxRating string) bool {
+	rating, ok := contentRatings[strings.ToUpper(strings.TrimSpace(s.ContentRating))]
+	if !ok {
+		return false
+	}
+	max, ok := contentRatings[strings.ToUpper(strings.TrimSpace(maxRating))]
+	return ok && rating <= max
+}
+
+// Validate checks the series for missing metadata and returns an error
+// describing each problem found, or nil if there are none.  A series can
+// decode without error and still fail these checks as TheTVDB leaves many
+// fields blank.  Use ValidateEpisodes to check its episodes.
+func (s *Series) Validate() []error {
+	var errs []error
+	if s.ID <= 0 {
+		errs = append(errs, fmt.Errorf("tvdb: invalid series ID '%d'", s.ID))
+	}
+	if strings.TrimSpace(s.Name) == "" {
+		errs = append(errs, errors.New("tvdb: series has no name"))
+	}
+	if s.FirstAired.IsZero() {
+		errs = append(errs, errors.New("tvdb: series has no first aired date"))
+	}
+	return errs
+}
+
+// ValidateEpisodes checks the episodes fetched with a series, such as by
+// SeriesAllByID, when they were expected to be there and returns an error
+// for each problem found, or nil if there are none.
+func ValidateEpisodes(episodes EpisodeList) []error {
+	if len(episodes) == 0 {
+		return []error{errors.New("tvdb: series has no episodes")}
+	}
+	return nil
+}
+
+// seriesJSON is the document written by WriteSeriesJSON.
+type seriesJSON struct {
+	Series   *Series     `json:"series"`
+	Episodes EpisodeList `json:"episodes"`
+}
+
+// WriteSeriesJSON writes a series and its episodes, such as those returned by
+// SeriesAllByID, to w as JSON so they can be loaded later with ReadSeriesJSON
+// instead of fetching them again.  Episodes are written in season and episode
+// order so the same series always gives the same output.
+func WriteSeriesJSON(w io.Writer, s *Series, episodes EpisodeList) error {
+	sorted := EpisodeList{}
+	for _, ep := range episodes.Sorted() {
+		sorted = append(sorted, *ep)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(seriesJSON{Series: s, Episodes: sorted})
+}
+
+// ReadSeriesJSON reads a series and its episodes written by WriteSeriesJSON.
+func ReadSeriesJSON(r io.Reader) (*Series, EpisodeList, error) {
+	var doc seriesJSON
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+	return doc.Series, doc.Episodes, nil
+}
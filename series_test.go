@@ -0,0 +1,199 @@
+package tvdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestSeriesListFindByName(t *testing.T) {
+	series := SeriesList{
+		{ID: 1, Name: "The Office (US)"},
+		{ID: 2, Name: "The Office"},
+		{ID: 3, Name: "the office"},
+	}
+
+	if s, ok := series.FindByName("THE OFFICE"); !ok || s.ID != 2 {
+		t.Errorf("Expected series '2' got '%v' (%v)", s, ok)
+	}
+	if _, ok := series.FindByName("Office"); ok {
+		t.Error("Expected no exact match for a partial name")
+	}
+}
+
+func TestSeriesListFilterByName(t *testing.T) {
+	series := SeriesList{
+		{ID: 1, Name: "The Office (US)"},
+		{ID: 2, Name: "Parks and Recreation"},
+		{ID: 3, Name: "The Office"},
+	}
+
+	ids := []int{}
+	for _, s := range series.FilterByName("office") {
+		ids = append(ids, s.ID)
+	}
+	if want := []int{1, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expected series '%v' got '%v'", want, ids)
+	}
+}
+
+func TestContentRatingAtMost(t *testing.T) {
+	tests := []struct {
+		rating, max string
+		want        bool
+	}{
+		{"TV-PG", "TV-14", true},
+		{"TV-14", "TV-14", true},
+		{"TV-MA", "TV-14", false},
+		{"tv-y7-fv", "TV-Y7", true},
+		{"TV-G", "TV-Y", false},
+		{"", "TV-MA", false},
+		{"Unrated", "TV-MA", false},
+		{"TV-G", "R", false},
+	}
+
+	for _, test := range tests {
+		s := &Series{ContentRating: test.rating}
+		if got := s.ContentRatingAtMost(test.max); got != test.want {
+			t.Errorf("Rating '%s' at most '%s' expected '%t' got '%t'", test.rating, test.max, test.want, got)
+		}
+	}
+}
+
+func TestSeriesJSONRoundTrip(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_all_en.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), handler)
+
+	series, episodes, err := client.SeriesAllByID(context.Background(), 71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	episodes.SortBySeasonEpisode()
+
+	var buf bytes.Buffer
+	if err := WriteSeriesJSON(&buf, series, episodes); err != nil {
+		t.Fatal(err)
+	}
+	gotSeries, gotEpisodes, err := ReadSeriesJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := series.Diff(gotSeries); len(diff) != 0 || !gotSeries.LastUpdated.Equal(series.LastUpdated.Time) {
+		t.Errorf("Series does not match after reading it back: %v", diff)
+	}
+	if len(gotEpisodes) != len(episodes) {
+		t.Fatalf("Expected '%d' episodes got '%d'", len(episodes), len(gotEpisodes))
+	}
+	for i := range episodes {
+		want, got := episodes[i], gotEpisodes[i]
+		if got.ID != want.ID || got.EpisodeName != want.EpisodeName || !got.FirstAired.Equal(want.FirstAired.Time) ||
+			got.Rating != want.Rating || got.DVDSeason != want.DVDSeason || !reflect.DeepEqual(got.GuestStars, want.GuestStars) {
+			t.Errorf("Episode '%d' does not match after reading it back.  \n%s", want.ID, pretty.Compare(want, got))
+		}
+	}
+}
+
+func TestSeriesListRanked(t *testing.T) {
+	l := SeriesList{
+		{ID: 1, Name: "Jessica Simpson's The Price of Beauty"},
+		{ID: 2, Name: "The Simpsons Movie Special"},
+		{ID: 3, Name: "Simpson"},
+		{ID: 4, Name: "The Simpsons!"},
+		{ID: 5, Name: "Meet the Simpsons"},
+	}
+
+	ranked := l.Ranked("the simpsons")
+	var got []int
+	for _, r := range ranked {
+		got = append(got, r.ID)
+	}
+	if want := []int{4, 2, 5, 3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected order '%v' got '%v'", want, got)
+	}
+	if ranked[0].Score != 1 {
+		t.Errorf("Expected exact match score '1' got '%v'", ranked[0].Score)
+	}
+	for i := 1; i < len(ranked); i++ {
+		if ranked[i].Score >= ranked[i-1].Score || ranked[i].Score < 0 {
+			t.Errorf("Expected scores to fall got '%v' after '%v'", ranked[i].Score, ranked[i-1].Score)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"futurama", "futurma", 1},
+		{"café", "cafe", 1},
+	}
+	for _, test := range tests {
+		if got := levenshtein([]rune(test.a), []rune(test.b)); got != test.want {
+			t.Errorf("Distance '%s' '%s' expected '%d' got '%d'", test.a, test.b, test.want, got)
+		}
+	}
+}
+
+func TestSeriesValidate(t *testing.T) {
+	valid := &Series{ID: 71663, Name: "The Simpsons", FirstAired: Date(1989, time.December, 17)}
+	if errs := valid.Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors got '%v'", errs)
+	}
+
+	invalid := &Series{Name: " "}
+	if errs := invalid.Validate(); len(errs) != 3 {
+		t.Errorf("Expected '3' errors got '%v'", errs)
+	}
+
+	if errs := ValidateEpisodes(EpisodeList{{ID: 55452}}); len(errs) != 0 {
+		t.Errorf("Expected no errors got '%v'", errs)
+	}
+	if errs := ValidateEpisodes(nil); len(errs) != 1 {
+		t.Errorf("Expected '1' error got '%v'", errs)
+	}
+}
+
+func TestMatchesTitle(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8" ?><Data><Series>
+<id>79126</id><SeriesName>The Wire</SeriesName><AliasNames>Bodymore|Wire, The</AliasNames>
+</Series></Data>`
+	s, err := ParseSeries(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (pipeList{"Bodymore", "Wire, The"}); !reflect.DeepEqual(s.Aliases, want) {
+		t.Errorf("Expected aliases '%v' got '%v'", want, s.Aliases)
+	}
+
+	for title, want := range map[string]bool{
+		"The Wire":   true,
+		" the wire ": true,
+		"WIRE, THE":  true,
+		"bodymore":   true,
+		"Wire":       false,
+		"":           false,
+	} {
+		if got := s.MatchesTitle(title); got != want {
+			t.Errorf("Title '%s' expected '%v' got '%v'", title, want, got)
+		}
+	}
+
+	summary := &SeriesSummary{Name: "The Wire", Aliases: pipeList{"Bodymore"}}
+	if !summary.MatchesTitle("BODYMORE") {
+		t.Errorf("Expected summary to match alias 'BODYMORE'")
+	}
+}
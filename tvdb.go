@@ -1,13 +1,29 @@
 package tvdb
 
 import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/time/rate"
 )
 
 // pipeList type representing pipe-separated string values.
@@ -20,15 +36,38 @@ func (p *pipeList) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) er
 		return err
 	}
 
-	// Empty contents mean just use an empty list
-	if content != "" {
-		*p = strings.Split(strings.Trim(content, "|"), "|")
-	} else {
-		*p = []string{}
+	// Empty contents mean just use an empty list.  Blank entries such as
+	// those in "|" or "|a||b|" are dropped so they don't show up as phantom
+	// values.
+	*p = []string{}
+	for _, v := range strings.Split(content, "|") {
+		if v = strings.TrimSpace(v); v != "" {
+			*p = append(*p, v)
+		}
 	}
 	return nil
 }
 
+// MarshalXML marshals the list into an element with the values wrapped in
+// pipes the way TheTVDB does, e.g. "|Animation|Comedy|".  An empty list is
+// marshalled as an empty element.
+func (p pipeList) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	content := ""
+	if len(p) > 0 {
+		content = "|" + strings.Join(p, "|") + "|"
+	}
+	return encoder.EncodeElement(content, start)
+}
+
+// MarshalJSON marshals the list as a JSON array.  An empty list is
+// marshalled as [] rather than null.
+func (p pipeList) MarshalJSON() ([]byte, error) {
+	if p == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(p))
+}
+
 type ImgFlag int
 
 func (f ImgFlag) IsValid() bool {
@@ -87,14 +126,21 @@ func NullInt(i int) nullInt {
 }
 
 func (i *nullInt) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
-	var j int
-	err := decoder.DecodeElement(&j, &start)
+	var s string
+	if err := decoder.DecodeElement(&s, &start); err != nil {
+		return err
+	}
 
-	// Check for emptry string
-	if nerr, ok := err.(*strconv.NumError); ok && nerr.Num == "" {
+	// Check for emptry string.  Decoding straight into an int would silently
+	// give a valid 0 on newer versions of encoding/xml.
+	s = strings.TrimSpace(s)
+	if s == "" {
 		// Returns the zero values which will be 0, false
 		return nil
-	} else if err != nil {
+	}
+
+	j, err := strconv.Atoi(s)
+	if err != nil {
 		return err
 	}
 	i.Value = j
@@ -105,6 +151,27 @@ func (i *nullInt) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) err
 
 var NulInt = nullInt{0, false}
 
+// MarshalJSON marshals the value as a JSON number or null if it is not valid.
+func (i nullInt) MarshalJSON() ([]byte, error) {
+	if !i.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(i.Value)
+}
+
+// UnmarshalJSON unmarshals a JSON number or null.
+func (i *nullInt) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*i = NulInt
+		return nil
+	}
+	if err := json.Unmarshal(b, &i.Value); err != nil {
+		return err
+	}
+	i.Valid = true
+	return nil
+}
+
 type nullFloat64 struct {
 	Value float64
 	Valid bool
@@ -115,14 +182,21 @@ func NullFloat64(f float64) nullFloat64 {
 }
 
 func (f *nullFloat64) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
-	var j float64
-	err := decoder.DecodeElement(&j, &start)
+	var s string
+	if err := decoder.DecodeElement(&s, &start); err != nil {
+		return err
+	}
 
-	// Check for emptry string
-	if nerr, ok := err.(*strconv.NumError); ok && nerr.Num == "" {
+	// Check for emptry string.  Decoding straight into a float64 would
+	// silently give a valid 0 on newer versions of encoding/xml.
+	s = strings.TrimSpace(s)
+	if s == "" {
 		// Returns the zero values which will be 0, false
 		return nil
-	} else if err != nil {
+	}
+
+	j, err := strconv.ParseFloat(s, 64)
+	if err != nil {
 		return err
 	}
 	f.Value = j
@@ -133,16 +207,61 @@ func (f *nullFloat64) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement)
 
 var NulFloat64 = nullFloat64{0, false}
 
+// MarshalJSON marshals the value as a JSON number or null if it is not valid.
+func (f nullFloat64) MarshalJSON() ([]byte, error) {
+	if !f.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(f.Value)
+}
+
+// UnmarshalJSON unmarshals a JSON number or null.
+func (f *nullFloat64) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*f = NulFloat64
+		return nil
+	}
+	if err := json.Unmarshal(b, &f.Value); err != nil {
+		return err
+	}
+	f.Valid = true
+	return nil
+}
+
 type unixTime struct {
 	time.Time
 }
 
 func (t *unixTime) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
-	var ut int64
-	if err := decoder.DecodeElement(&ut, &start); err != nil {
+	var s string
+	if err := decoder.DecodeElement(&s, &start); err != nil {
 		return err
 	}
+	return t.parse(s)
+}
 
+func (t *unixTime) UnmarshalXMLAttr(attr xml.Attr) error {
+	return t.parse(attr.Value)
+}
+
+// parse sets the time from a string of epoch seconds.  A blank string or 0
+// gives the zero time rather than the start of the epoch.
+func (t *unixTime) parse(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	ut, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	// Records that were never updated have a timestamp of 0
+	if ut == 0 {
+		t.Time = time.Time{}
+		return nil
+	}
 	t.Time = time.Unix(ut, int64(0)).UTC()
 	return nil
 }
@@ -174,6 +293,25 @@ func (t *dateTime) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) er
 
 var NullDateTime = DateTime(0, time.January, 0, 0, 0, 0)
 
+// MarshalJSON marshals the time as an RFC 3339 string or null if it wasn't
+// set.
+func (t dateTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() || t.Equal(NullDateTime.Time) {
+		return []byte("null"), nil
+	}
+	return t.Time.MarshalJSON()
+}
+
+// UnmarshalJSON unmarshals an RFC 3339 string or null which gives
+// NullDateTime.
+func (t *dateTime) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*t = NullDateTime
+		return nil
+	}
+	return t.Time.UnmarshalJSON(b)
+}
+
 type date struct {
 	time.Time
 }
@@ -199,94 +337,270 @@ func (t *date) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error
 	return err
 }
 
+// MarshalJSON marshals the date as a "2006-01-02" string or null if it wasn't
+// set.
+func (t date) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Format("2006-01-02"))
+}
+
+// UnmarshalJSON unmarshals a "2006-01-02" string or null which gives the zero
+// date.
+func (t *date) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+	var ts string
+	if err := json.Unmarshal(b, &ts); err != nil {
+		return err
+	}
+
+	var err error
+	t.Time, err = time.Parse("2006-01-02", ts)
+	return err
+}
+
 // Episode represents a TV show episode on TheTVDB.
 type Episode struct {
-	ID                    int         `xml:"id"`
-	CombinedEpisodeNumber string      `xml:"Combined_episodenumber"`
-	CombinedSeason        int         `xml:"Combined_season"`
-	DVDEpisodeNumber      string      `xml:"DVD_episodenumber,omitempty"`
-	DVDSeason             nullInt     `xml:"DVD_season,omitempty"`
-	Director              pipeList    `xml:"Director"`
-	EpImgFlag             ImgFlag     `xml:"EpImgFlag"`
-	EpisodeName           string      `xml:"EpisodeName"`
-	EpisodeNumber         int         `xml:"EpisodeNumber"`
-	FirstAired            date        `xml:"FirstAired"`
-	GuestStars            pipeList    `xml:"GuestStars"`
-	IMDBID                string      `xml:"IMDB_ID"`
-	Language              string      `xml:"Language"`
-	Overview              string      `xml:"Overview"`
-	ProductionCode        string      `xml:"ProductionCode"`
-	Rating                nullFloat64 `xml:"Rating"`
-	RatingCount           nullInt     `xml:"RatingCount"`
-	SeasonNumber          int         `xml:"SeasonNumber"`
-	Writer                pipeList    `xml:"Writer"`
-	AbsoluteNumber        nullInt     `xml:"absolute_number"`
-	BannerFilename        string      `xml:"filename"`
-	LastUpdated           unixTime    `xml:"lastupdated"`
-	SeasonID              int         `xml:"seasonid"`
-	SeriesID              int         `xml:"seriesid"`
-	ThumbAdded            dateTime    `xml:"thumb_added"`
-	ThumbHeight           nullInt     `xml:"thumb_height"`
-	ThumbWidth            nullInt     `xml:"thumb_width"`
+	ID                    int         `xml:"id" json:"id"`
+	CombinedEpisodeNumber string      `xml:"Combined_episodenumber" json:"combined_episode_number"`
+	CombinedSeason        int         `xml:"Combined_season" json:"combined_season"`
+	DVDEpisodeNumber      string      `xml:"DVD_episodenumber,omitempty" json:"dvd_episode_number"`
+	DVDSeason             nullInt     `xml:"DVD_season,omitempty" json:"dvd_season"`
+	Director              pipeList    `xml:"Director" json:"director"`
+	EpImgFlag             ImgFlag     `xml:"EpImgFlag" json:"ep_img_flag"`
+	EpisodeName           string      `xml:"EpisodeName" json:"episode_name"`
+	EpisodeNumber         int         `xml:"EpisodeNumber" json:"episode_number"`
+	FirstAired            date        `xml:"FirstAired" json:"first_aired"`
+	GuestStars            pipeList    `xml:"GuestStars" json:"guest_stars"`
+	IMDBID                string      `xml:"IMDB_ID" json:"imdb_id"`
+	Language              string      `xml:"Language" json:"language"`
+	Overview              string      `xml:"Overview" json:"overview"`
+	ProductionCode        string      `xml:"ProductionCode" json:"production_code"`
+	Rating                nullFloat64 `xml:"Rating" json:"rating"`
+	RatingCount           nullInt     `xml:"RatingCount" json:"rating_count"`
+	SeasonNumber          int         `xml:"SeasonNumber" json:"season_number"`
+	Writer                pipeList    `xml:"Writer" json:"writer"`
+	AbsoluteNumber        nullInt     `xml:"absolute_number" json:"absolute_number"`
+	BannerFilename        string      `xml:"filename" json:"banner_filename"`
+	LastUpdated           unixTime    `xml:"lastupdated" json:"last_updated"`
+	SeasonID              int         `xml:"seasonid" json:"season_id"`
+	SeriesID              int         `xml:"seriesid" json:"series_id"`
+	ThumbAdded            dateTime    `xml:"thumb_added" json:"thumb_added"`
+	ThumbHeight           nullInt     `xml:"thumb_height" json:"thumb_height"`
+	ThumbWidth            nullInt     `xml:"thumb_width" json:"thumb_width"`
 	// Deprecated
 	//DvdChapter            int   `xml:"DVD_chapter"`
 	//DvdDiscID             string   `xml:"DVD_discid"`
 }
 
+// TrimmedProductionCode returns ProductionCode without surrounding
+// whitespace.
+func (e Episode) TrimmedProductionCode() string {
+	return strings.TrimSpace(e.ProductionCode)
+}
+
+// String returns the season and episode number followed by the episode name,
+// e.g. "S01E03 — Bushwhacked".  Only the numbers are returned when the episode
+// has no name.
+func (e Episode) String() string {
+	code := fmt.Sprintf("S%02dE%02d", e.SeasonNumber, e.EpisodeNumber)
+	if e.EpisodeName == "" {
+		return code
+	}
+	return code + " — " + e.EpisodeName
+}
+
 // SeriesSummary is returned from GetSeries
 type SeriesSummary struct {
-	ID         int      `xml:"id"`
-	Language   string   `xml:"language"`
-	Name       string   `xml:"SeriesName"`
-	BannerPath string   `xml:"banner"`
-	Overview   string   `xml:"Overview"`
-	FirstAired date     `xml:"FirstAired"`
-	IMDBID     string   `xml:"IMDB_ID"`
-	Zap2itID   string   `xml:"zap2it_id"`
-	Network    string   `xml:"Network"`
-	Aliases    pipeList `xml:"AliasNames,omitempty"`
+	ID         int      `xml:"id" json:"id"`
+	Language   string   `xml:"language" json:"language"`
+	Name       string   `xml:"SeriesName" json:"name"`
+	BannerPath string   `xml:"banner" json:"banner_path"`
+	Overview   string   `xml:"Overview" json:"overview"`
+	FirstAired date     `xml:"FirstAired" json:"first_aired"`
+	IMDBID     string   `xml:"IMDB_ID" json:"imdb_id"`
+	Zap2itID   string   `xml:"zap2it_id" json:"zap2it_id"`
+	Network    string   `xml:"Network" json:"network"`
+	Aliases    pipeList `xml:"AliasNames,omitempty" json:"aliases"`
 }
 
 // Series represents TV show on TheTVDB.
 type Series struct {
-	ID            int         `xml:"id"`
-	Language      string      `xml:"language"`
-	Name          string      `xml:"SeriesName"`
-	BannerPath    string      `xml:"banner"`
-	Overview      string      `xml:"Overview"`
-	FirstAired    date        `xml:"FirstAired"`
-	IMDBID        string      `xml:"IMDB_ID"`
-	Zap2itID      string      `xml:"zap2it_id"`
-	Network       string      `xml:"Network"`
-	Actors        pipeList    `xml:"Actors"`
-	AirsDayOfWeek string      `xml:"Airs_DayOfWeek"`
-	AirsTime      string      `xml:"Airs_Time"`
-	ContentRating string      `xml:"ContentRating"`
-	Genre         pipeList    `xml:"Genre"`
-	Rating        nullFloat64 `xml:"Rating"`
-	RatingCount   nullInt     `xml:"RatingCount"`
-	Runtime       nullInt     `xml:"Runtime"`
-	Status        string      `xml:"Status"` //TODO: Should be parsed
-	Added         dateTime    `xml:"added"`
-	AddedBy       nullInt     `xml:"addedBy"`
-	FanartPath    string      `xml:"fanart"`
-	PostersPath   string      `xml:"posters"`
-	LastUpdated   unixTime    `xml:"lastupdated"`
+	ID            int         `xml:"id" json:"id"`
+	Language      string      `xml:"language" json:"language"`
+	Name          string      `xml:"SeriesName" json:"name"`
+	Aliases       pipeList    `xml:"AliasNames" json:"aliases"`
+	BannerPath    string      `xml:"banner" json:"banner_path"`
+	Overview      string      `xml:"Overview" json:"overview"`
+	FirstAired    date        `xml:"FirstAired" json:"first_aired"`
+	IMDBID        string      `xml:"IMDB_ID" json:"imdb_id"`
+	Zap2itID      string      `xml:"zap2it_id" json:"zap2it_id"`
+	Network       string      `xml:"Network" json:"network"`
+	Actors        pipeList    `xml:"Actors" json:"actors"`
+	AirsDayOfWeek string      `xml:"Airs_DayOfWeek" json:"airs_day_of_week"`
+	AirsTime      string      `xml:"Airs_Time" json:"airs_time"`
+	ContentRating string      `xml:"ContentRating" json:"content_rating"`
+	Genre         pipeList    `xml:"Genre" json:"genre"`
+	Rating        nullFloat64 `xml:"Rating" json:"rating"`
+	RatingCount   nullInt     `xml:"RatingCount" json:"rating_count"`
+	Runtime       nullInt     `xml:"Runtime" json:"runtime"`
+	Status        string      `xml:"Status" json:"status"` //TODO: Should be parsed
+	Added         dateTime    `xml:"added" json:"added"`
+	AddedBy       nullInt     `xml:"addedBy" json:"added_by"`
+	FanartPath    string      `xml:"fanart" json:"fanart_path"`
+	PostersPath   string      `xml:"posters" json:"posters_path"`
+	LastUpdated   unixTime    `xml:"lastupdated" json:"last_updated"`
+}
+
+// String returns the series name followed by the year it first aired and its
+// ID, e.g. "Firefly (2002) [78874]".  The year is left out when it isn't
+// known.
+func (s Series) String() string {
+	name := s.Name
+	if name == "" {
+		name = "Unknown"
+	}
+	if s.FirstAired.IsZero() {
+		return fmt.Sprintf("%s [%d]", name, s.ID)
+	}
+	return fmt.Sprintf("%s (%d) [%d]", name, s.FirstAired.Year(), s.ID)
+}
+
+// Equal reports whether two series have the same metadata.  LastUpdated is
+// ignored since it changes even when nothing else does.
+func (s *Series) Equal(other *Series) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return len(s.Diff(other)) == 0
+}
+
+// Diff returns the names of the fields that differ between two series, which
+// is useful for deciding whether a re-fetched series needs to be stored.
+// LastUpdated is ignored and empty and missing lists are treated the same.
+func (s *Series) Diff(other *Series) []string {
+	var a, b Series
+	if s != nil {
+		a = *s
+	}
+	if other != nil {
+		b = *other
+	}
+
+	changed := []string{}
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := 0; i < av.NumField(); i++ {
+		name := av.Type().Field(i).Name
+		if name == "LastUpdated" {
+			continue
+		}
+		if !equalField(av.Field(i).Interface(), bv.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// equalField compares two field values of the same type.
+func equalField(a, b interface{}) bool {
+	switch a := a.(type) {
+	case pipeList:
+		b := b.(pipeList)
+		if len(a) == 0 && len(b) == 0 {
+			return true
+		}
+		return reflect.DeepEqual(a, b)
+	case date:
+		return a.Equal(b.(date).Time)
+	case dateTime:
+		return a.Equal(b.(dateTime).Time)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// PrimaryGenre returns the first genre of the series or "" if it has none.
+func (s Series) PrimaryGenre() string {
+	for _, g := range s.Genre {
+		if g != "" {
+			return g
+		}
+	}
+	return ""
+}
+
+// HasGenre reports whether the series has the genre ignoring case.
+func (s Series) HasGenre(name string) bool {
+	name = strings.TrimSpace(name)
+	for _, g := range s.Genre {
+		if strings.EqualFold(g, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEnded reports whether the series has finished airing.
+func (s Series) IsEnded() bool {
+	return strings.EqualFold(strings.TrimSpace(s.Status), "Ended")
+}
+
+// IsContinuing reports whether the series is still airing.
+func (s Series) IsContinuing() bool {
+	return strings.EqualFold(strings.TrimSpace(s.Status), "Continuing")
+}
+
+// Actor is a member of the cast of a series.
+type Actor struct {
+	ID        int    `xml:"id" json:"id"`
+	Name      string `xml:"Name" json:"name"`
+	Role      string `xml:"Role" json:"role"`
+	SortOrder int    `xml:"SortOrder" json:"sort_order"`
+	ImagePath string `xml:"Image" json:"image_path"`
+}
+
+// BannerType is the kind of artwork a Banner is.
+type BannerType string
+
+const (
+	BannerFanart = BannerType("fanart")
+	BannerPoster = BannerType("poster")
+	BannerSeason = BannerType("season")
+	BannerSeries = BannerType("series")
+)
+
+// Banner is a single piece of artwork for a series.  BannerType2 further
+// describes the artwork and is either its resolution for fanart and posters
+// or a style such as "graphical" and "seasonwide".
+type Banner struct {
+	ID            int         `xml:"id" json:"id"`
+	BannerPath    string      `xml:"BannerPath" json:"banner_path"`
+	BannerType    BannerType  `xml:"BannerType" json:"banner_type"`
+	BannerType2   string      `xml:"BannerType2" json:"banner_type2"`
+	Colors        pipeList    `xml:"Colors" json:"colors"`
+	Language      string      `xml:"Language" json:"language"`
+	Rating        nullFloat64 `xml:"Rating" json:"rating"`
+	RatingCount   nullInt     `xml:"RatingCount" json:"rating_count"`
+	Season        nullInt     `xml:"Season" json:"season"`
+	ThumbnailPath string      `xml:"ThumbnailPath" json:"thumbnail_path"`
 }
 
 // Langage format used for Client responses.
 type Language struct {
-	ID   int    `xml:"id"`
-	Abbr string `xml:"abbreviation"`
-	Name string `xml:"name"`
+	ID   int    `xml:"id" json:"id"`
+	Abbr string `xml:"abbreviation" json:"abbr"`
+	Name string `xml:"name" json:"name"`
 }
 
 // Rating of a show or episode for both user rating as well as community
 // rating.
 type Rating struct {
-	ID              int `xml:"id"`
-	UserRating      int
-	CommunityRating float32
+	ID              int     `xml:"id" json:"id"`
+	UserRating      int     `json:"user_rating"`
+	CommunityRating float32 `json:"community_rating"`
 }
 
 // UnmashalXML on Raiting is a hack to combine xml feilds id and seriesid into
@@ -321,49 +635,780 @@ const (
 	Zap2it = RemoteService("zap2it")
 )
 
+// Order is a way of numbering the episodes of a series.
+type Order string
+
+const (
+	OrderDefault  = Order("default")
+	OrderDVD      = Order("dvd")
+	OrderAbsolute = Order("absolute")
+)
+
+// normalizeIMDBID turns IMDb IDs such as "0903747" or " TT0903747 " into the
+// "tt0903747" form the API expects.
+func normalizeIMDBID(id string) (string, error) {
+	digits := strings.TrimSpace(id)
+	if len(digits) >= 2 && strings.EqualFold(digits[:2], "tt") {
+		digits = digits[2:]
+	}
+	if digits == "" {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalidIMDBID, id)
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: '%s'", ErrInvalidIMDBID, id)
+		}
+	}
+
+	// IDs are zero padded to at least 7 digits
+	if len(digits) < 7 {
+		digits = strings.Repeat("0", 7-len(digits)) + digits
+	}
+	return "tt" + digits, nil
+}
+
+// imdbURL returns the IMDb page for an IMDb ID or an empty string if the ID
+// is blank or invalid.
+func imdbURL(id string) string {
+	id, err := normalizeIMDBID(id)
+	if err != nil {
+		return ""
+	}
+	return "https://www.imdb.com/title/" + id + "/"
+}
+
+// IMDBURL returns the IMDb page of the series or an empty string if it has no
+// IMDb ID.
+func (s *SeriesSummary) IMDBURL() string {
+	return imdbURL(s.IMDBID)
+}
+
+// IMDBURL returns the IMDb page of the series or an empty string if it has no
+// IMDb ID.
+func (s *Series) IMDBURL() string {
+	return imdbURL(s.IMDBID)
+}
+
+// IMDBURL returns the IMDb page of the episode or an empty string if it has no
+// IMDb ID.
+func (e *Episode) IMDBURL() string {
+	return imdbURL(e.IMDBID)
+}
+
+// HTTPError is returned when an API request comes back with a non-200 status
+// code.  The body of these responses is usually an HTML error page so it is
+// not parsed.
+type HTTPError struct {
+	StatusCode int
+	URL        string
+
+	// RetryAfter is how long the server asked us to wait before trying
+	// again, from the Retry-After header of 429 and 503 responses.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("Failed request for '%s' got code '%d'", e.URL, e.StatusCode)
+}
+
+// Is allows errors.Is(err, ErrNotFound) to match 404 responses and
+// errors.Is(err, ErrAPIDeprecated) to match 410 responses.
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrAPIDeprecated:
+		return e.StatusCode == http.StatusGone
+	}
+	return false
+}
+
+var (
+	// ErrNotFound is returned when the requested item doesn't exist on
+	// TheTVDB.
+	ErrNotFound = errors.New("tvdb: not found")
+
+	// ErrMultipleSeries is returned when a lookup that should identify a
+	// single series matches more than one.
+	ErrMultipleSeries = errors.New("tvdb: multiple series found")
+
+	// ErrInvalidAPIKey is returned by Validate when the API key is blank or
+	// is rejected by TheTVDB.
+	ErrInvalidAPIKey = errors.New("tvdb: invalid API key")
+
+	// ErrNoSeriesID is returned by SeriesForEpisode when the episode isn't
+	// linked to a series.
+	ErrNoSeriesID = errors.New("tvdb: episode has no series ID")
+
+	// ErrInvalidIMDBID is returned when an IMDb ID can't be normalized.
+	ErrInvalidIMDBID = errors.New("tvdb: invalid IMDb ID")
+
+	// ErrAPIDeprecated is returned when TheTVDB reports that the XML API has
+	// been retired, either with a 410 response or an HTML page saying so.
+	ErrAPIDeprecated = errors.New("tvdb: API deprecated")
+)
+
+// DefaultLanguage is the language used when none is given to a call and no
+// Language is set on the Client.
+const DefaultLanguage = "en"
+
+// DefaultUserAgent is sent with requests from a Client with no UserAgent set.
+const DefaultUserAgent = "go-tvdb/1.0"
+
+// DefaultRetryBackoff is the base retry delay used when a Client has no
+// RetryBackoff set.
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+// DefaultTimeout is the overall time limit for a request made with the
+// http.Client created by NewClient.  Use WithHTTPClient to change it or the
+// DefaultTransport it uses.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultTransport returns a new http.Transport tuned for TheTVDB which
+// NewClient uses and which can be changed and passed to WithHTTPClient.  It
+// is like http.DefaultTransport but keeps up to 16 idle connections to each
+// host, rather than 2, so batch calls running at a Concurrency above 2 reuse
+// connections instead of opening new ones.  Raise MaxIdleConnsPerHost to at
+// least the Concurrency in use and keep IdleConnTimeout under a couple of
+// minutes as TheTVDB drops idle connections itself.
+func DefaultTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
 // Client is the base of all API calls to thetvdb.com.
 type Client struct {
-	APIKey     string
-	BaseURL    *url.URL
-	HTTPClient *http.Client
+	APIKey string
+
+	// BaseURL is the root that all API URLs are built from, allowing requests
+	// to be sent to a mirror or a local stub.  If nil the canonical
+	// thetvdb.com host is used.
+	BaseURL *url.URL
+
+	// Language is the abbreviation of the language (e.g. "de") used when an
+	// empty language is passed to a call.  If empty DefaultLanguage is used.
+	Language string
+
+	// UserAgent is sent as the User-Agent header of every request.  If empty
+	// DefaultUserAgent is used.
+	UserAgent string
+
+	// HTTPClient is used to make all requests.  Any Doer, such as a fake
+	// returning canned responses in tests, can be used in place of an
+	// *http.Client.  If nil http.DefaultClient is used.
+	HTTPClient Doer
+
+	// MaxRetries is the number of times a request is retried after a network
+	// error, a 5xx, or a 429 response.  A Retry-After header on the response
+	// is used in place of RetryBackoff.  Zero disables retries.
+	MaxRetries int
+
+	// RetryBackoff is the base delay before the first retry.  It doubles
+	// with each subsequent retry.  If zero DefaultRetryBackoff is used.
+	RetryBackoff time.Duration
+
+	// RateLimiter, if set, throttles all requests made by the client.  For
+	// example rate.NewLimiter(2, 1) allows at most two requests per second.
+	RateLimiter *rate.Limiter
+
+	// Cache, if set, stores responses so repeated lookups don't hit the
+	// network.  Calls for user accounts such as favorites and ratings are
+	// never cached.
+	Cache Cache
+
+	// Concurrency is the maximum number of requests batch calls such as
+	// SeriesDetails make at once.  If zero DefaultConcurrency is used.
+	Concurrency int
+
+	// Deduplicate, if true, collapses identical requests made at the same
+	// time into a single request whose response is shared.  A caller whose
+	// context is cancelled stops waiting without cancelling the shared
+	// request for the others, and the request is cancelled once every caller
+	// has stopped waiting.  Calls that are never cached are never
+	// deduplicated.
+	Deduplicate bool
+	flightMu    sync.Mutex
+	flights     map[string]*flightCall
+
+	// OnRequest, if set, is called after every request attempt, including
+	// retries, with the time taken to receive the response headers and the
+	// error if the attempt failed.
+	OnRequest func(method, url string, dur time.Duration, err error)
+
+	// Observer, if set, is told when every request attempt, including
+	// retries, starts and finishes.
+	Observer Observer
+
+	// OnRawResponse, if set, is called with the undecoded body of every
+	// successful response read from the network, even when it fails to
+	// decode.  Responses served from the Cache are not passed to it.
+	OnRawResponse func(url string, body []byte)
+}
+
+// defaultBaseURL returns the URL of the canonical TheTVDB host.  HTTPS is
+// used so the API key and account IDs are not sent in cleartext.
+func defaultBaseURL() *url.URL {
+	return &url.URL{
+		Scheme: "https",
+		Host:   "thetvdb.com",
+	}
+}
+
+// Observer receives the start and end of every request attempt for exporting
+// metrics.  The endpoint is the name of the client method that made the
+// request, such as "SeriesByID", so it is safe to use as a metric label.
+type Observer interface {
+	RequestStarted(endpoint string)
+	RequestFinished(endpoint string, dur time.Duration, err error)
+}
+
+// endpointKey is the context key holding the endpoint label for Observer.
+type endpointKey struct{}
+
+// withEndpoint returns ctx labelled with the client method making requests.
+// Methods built on others, such as SeriesForEpisode, report the method they
+// call so the set of labels stays small.
+func withEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+// endpointFrom returns the endpoint label set on ctx or "unknown".
+func endpointFrom(ctx context.Context) string {
+	if endpoint, ok := ctx.Value(endpointKey{}).(string); ok {
+		return endpoint
+	}
+	return "unknown"
+}
+
+// Doer sends an HTTP request and returns its response.  *http.Client
+// implements Doer.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Option configures optional settings on a Client created by NewClient.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used for all requests, allowing
+// timeouts, proxies, TLS settings, and test transports to be configured.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		// Don't store a typed nil that would defeat the http.DefaultClient
+		// fallback
+		if hc == nil {
+			c.HTTPClient = nil
+			return
+		}
+		c.HTTPClient = hc
+	}
+}
+
+// WithDoer sets the Doer used for all requests.  This allows a fake that
+// returns canned responses to be used in tests without a server.
+func WithDoer(d Doer) Option {
+	return func(c *Client) {
+		c.HTTPClient = d
+	}
+}
+
+// WithLanguage sets the default language used when an empty language is
+// passed to a call.
+func WithLanguage(lang string) Option {
+	return func(c *Client) {
+		c.Language = lang
+	}
+}
+
+// WithBaseURL sets the root that all API URLs are built from.
+func WithBaseURL(u *url.URL) Option {
+	return func(c *Client) {
+		c.BaseURL = u
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.UserAgent = ua
+	}
+}
+
+// WithRateLimit throttles the client to r requests per second with bursts of
+// up to burst requests.
+func WithRateLimit(r rate.Limit, burst int) Option {
+	return func(c *Client) {
+		c.RateLimiter = rate.NewLimiter(r, burst)
+	}
 }
 
 // NewClient returns a new TVDB API instance.:
-func NewClient(apiKey string) *Client {
-	return &Client{
-		APIKey: apiKey,
-		BaseURL: &url.URL{
-			Scheme: "http",
-			Host:   "thetvdb.com",
-		},
-		HTTPClient: &http.Client{},
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		APIKey:     apiKey,
+		BaseURL:    defaultBaseURL(),
+		HTTPClient: &http.Client{Timeout: DefaultTimeout, Transport: DefaultTransport()},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
+
+// Close releases the resources held by the client.  Idle connections of the
+// HTTPClient are closed and the Cache is closed if it is an io.Closer, so
+// don't close a client whose Cache is shared with another one that is still
+// in use.  The client can still be used afterwards.
+func (c *Client) Close() error {
+	if hc, ok := c.HTTPClient.(interface{ CloseIdleConnections() }); ok {
+		hc.CloseIdleConnections()
+	}
+	if closer, ok := c.Cache.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// httpClient returns the configured HTTPClient or http.DefaultClient if none
+// has been set.
+func (c *Client) httpClient() Doer {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
 }
 
 // getReponse does the heavy lifting by fetching and decoding API responses.
-func (c *Client) getResponse(url string, v interface{}) error {
-	resp, err := c.HTTPClient.Get(url)
-	if err != nil {
+// The request is bound to ctx so cancelling it aborts both the request and
+// the decoding of the response body.  If the client has a Cache it is
+// consulted first and filled on success.  With Deduplicate set concurrent
+// calls for the same url share a single fetch.
+func (c *Client) getResponse(ctx context.Context, url string, v interface{}) error {
+	if c.Cache != nil {
+		if b, ok := c.Cache.Get(url); ok {
+			return decode(ctx, bytes.NewReader(b), v)
+		}
+	}
+
+	if c.Deduplicate {
+		b, err := c.sharedFetch(ctx, url)
+		if err != nil {
+			return err
+		}
+		if err := decode(ctx, bytes.NewReader(b), v); err != nil {
+			return err
+		}
+		if c.Cache != nil {
+			c.Cache.Set(url, b)
+		}
+		return nil
+	}
+
+	if c.Cache == nil {
+		return c.fetchResponse(ctx, url, v, nil)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := c.fetchResponse(ctx, url, v, buf); err != nil {
 		return err
 	}
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("Failed request for '%s' got code '%d'", url, resp.StatusCode)
+	c.Cache.Set(url, buf.Bytes())
+	return nil
+}
+
+// flightCall is a fetch shared by the concurrent callers of sharedFetch.
+type flightCall struct {
+	done    chan struct{}
+	body    []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// sharedFetch fetches the body of url joining any fetch of it already in
+// flight.  The fetch doesn't use the context of any one caller so a caller
+// giving up doesn't fail the others, but it is cancelled once every caller
+// has given up so it can't outlive them.
+func (c *Client) sharedFetch(ctx context.Context, url string) ([]byte, error) {
+	c.flightMu.Lock()
+	if c.flights == nil {
+		c.flights = make(map[string]*flightCall)
+	}
+	call, ok := c.flights[url]
+	if !ok {
+		fetchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		c.flights[url] = call
+		go func() {
+			buf := &bytes.Buffer{}
+			err := c.fetchResponse(fetchCtx, url, nil, buf)
+			cancel()
+
+			c.flightMu.Lock()
+			if c.flights[url] == call {
+				delete(c.flights, url)
+			}
+			c.flightMu.Unlock()
+
+			call.body, call.err = buf.Bytes(), err
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	c.flightMu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.body, call.err
+	case <-ctx.Done():
+		c.flightMu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			// Later callers start a new fetch rather than join a cancelled one
+			if c.flights[url] == call {
+				delete(c.flights, url)
+			}
+		}
+		c.flightMu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// fetchResponse fetches and decodes an API response bypassing any Cache.  If
+// raw is not nil the undecoded body is copied into it as it is read.
+func (c *Client) fetchResponse(ctx context.Context, url string, v interface{}, raw io.Writer) error {
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return err
 	}
 	defer resp.Body.Close()
 
-	d := xml.NewDecoder(resp.Body)
-	if err = d.Decode(v); err != nil {
+	if err := checkDeprecated(resp); err != nil {
+		return err
+	}
+
+	var body io.Reader = resp.Body
+	if raw != nil {
+		body = io.TeeReader(body, raw)
+	}
+	if c.OnRawResponse == nil {
+		return decode(ctx, body, v)
+	}
+
+	buf := &bytes.Buffer{}
+	body = io.TeeReader(body, buf)
+	err = decode(ctx, body, v)
+	// Read whatever the decoder left behind so the hook sees the whole body
+	io.Copy(ioutil.Discard, body)
+	c.OnRawResponse(url, buf.Bytes())
+	return err
+}
+
+// checkDeprecated returns ErrAPIDeprecated if a successful response is an HTML
+// page announcing that the API has been retired instead of an XML document.
+// The start of HTML bodies is read to check so resp.Body is replaced with a
+// reader that returns the whole body.
+func checkDeprecated(resp *http.Response) error {
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return nil
+	}
+
+	head := make([]byte, 4096)
+	n, err := io.ReadFull(resp.Body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
 		return err
 	}
+	head = head[:n]
+	if bytes.Contains(bytes.ToLower(head), []byte("deprecated")) {
+		return ErrAPIDeprecated
+	}
 
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
 	return nil
 }
 
+// decode decodes the XML document in r into v as it is read.  If v is nil the
+// document is read and discarded.
+func decode(ctx context.Context, r io.Reader, v interface{}) error {
+	if v == nil {
+		if _, err := io.Copy(ioutil.Discard, r); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return err
+		}
+		return nil
+	}
+
+	d := xml.NewDecoder(skipBOM(r))
+	// Older documents aren't always UTF-8
+	d.CharsetReader = charset.NewReaderLabel
+	if err := d.Decode(v); err != nil {
+		// A cancelled context surfaces as a read error mid-body
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+
+	return nil
+}
+
+// skipBOM returns a reader for r without the UTF-8 byte order mark some
+// documents start with.
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(3); err == nil && bytes.Equal(b, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+	return br
+}
+
+// get fetches url, retrying network errors, 5xx and 429 responses up to
+// MaxRetries times and waiting on the RateLimiter before each attempt.  Only
+// successful responses are returned and the caller is responsible for
+// closing the body.
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	return c.getWithHeader(ctx, url, nil)
+}
+
+// getWithHeader is like get but adds header to every attempt.  Responses with
+// a status in accept, such as 304 for a conditional request, are returned as
+// successful too.
+func (c *Client) getWithHeader(ctx context.Context, url string, header http.Header, accept ...int) (*http.Response, error) {
+	endpoint := endpointFrom(ctx)
+	for attempt := 0; ; attempt++ {
+		// Every attempt, including retries, counts against the rate limit
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		if c.Observer != nil {
+			c.Observer.RequestStarted(endpoint)
+		}
+		start := time.Now()
+		resp, err := c.doGet(ctx, url, header, accept)
+		dur := time.Since(start)
+		if c.OnRequest != nil {
+			c.OnRequest("GET", url, dur, err)
+		}
+		if c.Observer != nil {
+			c.Observer.RequestFinished(endpoint, dur, err)
+		}
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= c.MaxRetries || !retryable(err) {
+			return nil, err
+		}
+
+		// Honor the server's Retry-After over our own backoff
+		delay := c.backoff(attempt)
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.RetryAfter > 0 {
+			delay = httpErr.RetryAfter
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// doGet makes a single GET request for url with any extra header values.
+// Statuses other than 200 are errors unless they are in accept.
+func (c *Client) doGet(ctx context.Context, url string, header http.Header, accept []int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("User-Agent", userAgent(c.UserAgent))
+	// Setting this stops http.Transport from decompressing for us but makes
+	// sure any Doer asks for, and gets, a compressed body
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		// Report the cancellation rather than the transport's wrapped error
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 && !acceptStatus(resp.StatusCode, accept) {
+		// Always close the body, even on failed requests, so the connection
+		// can be returned to the transport's idle pool
+		resp.Body.Close()
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			URL:        url,
+			RetryAfter: retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		resp.Body = &gzipBody{Reader: zr, body: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+	}
+
+	return resp, nil
+}
+
+// acceptStatus reports whether code is one of accept.
+func acceptStatus(code int, accept []int) bool {
+	for _, a := range accept {
+		if code == a {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipBody decompresses a response body and closes both the decompressor and
+// the underlying body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// retryAfter parses a Retry-After header given either as a number of seconds
+// or as an HTTP date.  Zero is returned if the header is missing or invalid.
+func retryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// retryable reports whether a failed request should be tried again.  Only
+// transport errors, server side errors, and rate limited (429) responses are
+// considered transient.  Errors building the request or decoding the
+// response would only fail again.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	// A bad URL is reported as a *url.Error too
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Op != "parse"
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// maxRetryBackoff caps the delay between retries however many there are.
+const maxRetryBackoff = time.Minute
+
+// backoff returns how long to wait before retry number attempt (starting at
+// 0).  The delay doubles each attempt, up to maxRetryBackoff, with up to half
+// of it randomized so many clients don't retry in lockstep.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.RetryBackoff
+	if d <= 0 {
+		d = DefaultRetryBackoff
+	}
+	// Stop doubling before the shift can overflow
+	for i := 0; i < attempt && d < maxRetryBackoff; i++ {
+		d <<= 1
+	}
+	if d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// baseURL returns the configured BaseURL or the canonical host if none has
+// been set.
+func (c *Client) baseURL() *url.URL {
+	if c == nil || c.BaseURL == nil {
+		return defaultBaseURL()
+	}
+	return c.BaseURL
+}
+
+// userAgent returns ua or DefaultUserAgent if it is empty.
+func userAgent(ua string) string {
+	if ua == "" {
+		return DefaultUserAgent
+	}
+	return ua
+}
+
+// language returns lang if it is set, otherwise the client's Language, and
+// finally DefaultLanguage.
+func (c *Client) language(lang string) string {
+	if lang != "" {
+		return lang
+	}
+	if c.Language != "" {
+		return c.Language
+	}
+	return DefaultLanguage
+}
+
 // apiURL returns a base url for the dynamic API with fields already
-// populated.
+// populated.  Any path on BaseURL is kept as a prefix.
 func (c *Client) apiURL(path string, query url.Values) *url.URL {
-	u := *c.BaseURL
-	u.Path = fmt.Sprintf("api/%s", path)
+	u := *c.baseURL()
+	u.Path = fmt.Sprintf("%s/api/%s", strings.TrimSuffix(u.Path, "/"), path)
 	u.RawQuery = query.Encode()
 	return &u
 }
@@ -371,155 +1416,482 @@ func (c *Client) apiURL(path string, query url.Values) *url.URL {
 // staticAPIURL returns a base url for the static API with fields already
 // populated.
 func (c *Client) staticAPIURL(path string) *url.URL {
-	u := *c.BaseURL
-	u.Path = fmt.Sprintf("api/%s/%s", c.APIKey, path)
+	u := *c.baseURL()
+	u.Path = fmt.Sprintf("%s/api/%s/%s", strings.TrimSuffix(u.Path, "/"), c.APIKey, path)
 	return &u
 }
 
 // Lanauges gets a list of lanauges currently supported on TVDB.
-func (c *Client) Languages() ([]Language, error) {
+func (c *Client) Languages(ctx context.Context) ([]Language, error) {
+	ctx = withEndpoint(ctx, "Languages")
 	u := c.staticAPIURL("languages.xml")
 	response := struct {
 		XMLName xml.Name   `xml:"Languages"`
 		Langs   []Language `xml:"Language"`
 	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
+	if err := c.getResponse(ctx, u.String(), &response); err != nil {
 		return nil, err
 	}
 	return response.Langs, nil
 }
 
+// Validate checks the API key by making a cheap request that requires it so
+// a bad key can be caught at startup.  ErrInvalidAPIKey is returned if the key
+// is blank or rejected.  The response is never cached.
+func (c *Client) Validate(ctx context.Context) error {
+	ctx = withEndpoint(ctx, "Validate")
+	if strings.TrimSpace(c.APIKey) == "" {
+		return ErrInvalidAPIKey
+	}
+
+	u := c.staticAPIURL("languages.xml")
+	response := struct {
+		XMLName xml.Name   `xml:"Languages"`
+		Langs   []Language `xml:"Language"`
+	}{}
+	err := c.fetchResponse(ctx, u.String(), &response, nil)
+
+	// A rejected key gets an error page instead of the language list.  Being
+	// rate limited says nothing about the key.
+	var httpErr *HTTPError
+	var syntaxErr *xml.SyntaxError
+	var unmarshalErr xml.UnmarshalError
+	switch {
+	case errors.Is(err, ErrAPIDeprecated):
+		return err
+	case errors.As(err, &httpErr) && httpErr.StatusCode < 500 && httpErr.StatusCode != http.StatusTooManyRequests,
+		errors.As(err, &syntaxErr),
+		errors.As(err, &unmarshalErr):
+		return fmt.Errorf("%w: %v", ErrInvalidAPIKey, err)
+	case err != nil:
+		return err
+	case len(response.Langs) == 0:
+		return ErrInvalidAPIKey
+	}
+	return nil
+}
+
 // SearchSeries queries for a series by the series name. Returns a slice of
-// series summary data.
-// See http://thetvdb.com/wiki/index.php?title=API:GetSeries for more information
-func (c *Client) SearchSeries(term, lang string) ([]SeriesSummary, error) {
+// series summary data parsed from a single request with each series listed
+// only once and the closest matches to term first.  Use SeriesDetails to
+// fetch the full details for the results when they are needed.
+// See https://thetvdb.com/wiki/index.php?title=API:GetSeries for more information
+func (c *Client) SearchSeries(ctx context.Context, term, lang string) (SeriesList, error) {
+	ctx = withEndpoint(ctx, "SearchSeries")
 	query := url.Values{}
 	query.Set("seriesname", term)
+	if lang == "" {
+		lang = c.Language
+	}
 	if lang != "" {
 		query.Set("language", lang)
 	}
 
 	u := c.apiURL("GetSeries.php", query)
 
-	response := struct {
-		XMLName xml.Name `xml:"Data"`
-		Series  []SeriesSummary
-	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
+	response := seriesListDoc{}
+	if err := c.getResponse(ctx, u.String(), &response); err != nil {
+		return nil, err
+	}
+
+	// Series matching in more than one language are listed once per language
+	seen := make(map[int]bool)
+	series := response.Series[:0]
+	for _, s := range response.Series {
+		if !seen[s.ID] {
+			seen[s.ID] = true
+			series = append(series, s)
+		}
+	}
+
+	// The API doesn't order by relevance so exact titles can come last
+	for i, r := range series.Ranked(term) {
+		series[i] = r.SeriesSummary
+	}
+	return series, nil
+}
+
+// SearchSeriesIDs queries for a series by the series name like SearchSeries
+// but only returns the IDs of the matches, best first.  Nothing beyond the
+// single search request is fetched so details can be loaded later with
+// SeriesByID or SeriesByIDs.
+func (c *Client) SearchSeriesIDs(ctx context.Context, term, lang string) ([]int, error) {
+	series, err := c.SearchSeries(ctx, term, lang)
+	if err != nil {
 		return nil, err
 	}
-	return response.Series, nil
+	return series.IDs(), nil
 }
 
-// SeriesByID gets a single series' details from the TVDB series id.
-func (c *Client) SeriesByID(id int, lang string) (*Series, error) {
-	if lang == "" {
-		lang = "en"
+// SearchSeriesRanked queries for a series by the series name like
+// SearchSeries but also returns how closely each series' name matches term.
+func (c *Client) SearchSeriesRanked(ctx context.Context, term, lang string) ([]RankedSeries, error) {
+	series, err := c.SearchSeries(ctx, term, lang)
+	if err != nil {
+		return nil, err
 	}
-	u := c.staticAPIURL(fmt.Sprintf("series/%d/%s.xml", id, lang))
-	response := struct {
-		XMLName xml.Name `xml:"Data"`
-		Series  Series
-	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
+	return series.Ranked(term), nil
+}
+
+// SearchSeriesPage queries for a series by the series name like SearchSeries
+// but only returns up to limit results after skipping the first offset.  A
+// negative limit returns every result after offset and a limit of 0 returns
+// none.  The search API has no paging of its own so every page fetches the
+// full results unless a Cache is set.
+func (c *Client) SearchSeriesPage(ctx context.Context, term string, offset, limit int, lang string) (SeriesList, error) {
+	series, err := c.SearchSeries(ctx, term, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(series) {
+		offset = len(series)
+	}
+	series = series[offset:]
+	if limit >= 0 && limit < len(series) {
+		series = series[:limit]
+	}
+	return series, nil
+}
+
+// SearchSeriesYear queries for a series by the series name like SearchSeries
+// but only returns series that first aired within tolerance years of year.
+// This helps pick the right series when a title has been remade.  Series
+// without a first aired date are never returned.
+func (c *Client) SearchSeriesYear(ctx context.Context, term string, year, tolerance int, lang string) (SeriesList, error) {
+	series, err := c.SearchSeries(ctx, term, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := SeriesList{}
+	for _, s := range series {
+		if s.FirstAired.IsZero() {
+			continue
+		}
+		diff := s.FirstAired.Year() - year
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= tolerance {
+			matches = append(matches, s)
+		}
+	}
+	return matches, nil
+}
+
+// SeriesByID gets a single series' details from the TVDB series id.
+// ErrNotFound is returned if the series doesn't exist.
+func (c *Client) SeriesByID(ctx context.Context, id int, lang string) (*Series, error) {
+	ctx = withEndpoint(ctx, "SeriesByID")
+	u := c.staticAPIURL(fmt.Sprintf("series/%d/%s.xml", id, c.language(lang)))
+	response := seriesDoc{}
+	if err := c.getResponse(ctx, u.String(), &response); err != nil {
 		return nil, err
 	}
+	if response.Series.ID == 0 {
+		return nil, ErrNotFound
+	}
 
 	return &response.Series, nil
 }
 
+// SeriesForEpisode gets the series an episode belongs to using its SeriesID.
+// ErrNoSeriesID is returned if the episode has no SeriesID.
+func (c *Client) SeriesForEpisode(ctx context.Context, ep *Episode, lang string) (*Series, error) {
+	if ep == nil || ep.SeriesID == 0 {
+		return nil, ErrNoSeriesID
+	}
+	return c.SeriesByID(ctx, ep.SeriesID, lang)
+}
+
 // SeriesByRemoteID gets a singles series' details from an identifier from a
-// remote service like IMDB or Zap2it.
-// See: http://thetvdb.com/wiki/index.php?title=API:GetSeriesByRemoteID
-func (c *Client) SeriesByRemoteID(service RemoteService, id, lang string) (*SeriesSummary, error) {
+// remote service like IMDB or Zap2it.  ErrNotFound is returned if no series
+// matches and ErrMultipleSeries if more than one does.  IMDb IDs may be given
+// with or without the "tt" prefix.
+// See: https://thetvdb.com/wiki/index.php?title=API:GetSeriesByRemoteID
+func (c *Client) SeriesByRemoteID(ctx context.Context, service RemoteService, id, lang string) (*SeriesSummary, error) {
+	ctx = withEndpoint(ctx, "SeriesByRemoteID")
+	if service == IMDB {
+		var err error
+		if id, err = normalizeIMDBID(id); err != nil {
+			return nil, err
+		}
+	}
+
 	query := url.Values{}
 	query.Set(string(service), id)
+	if lang == "" {
+		lang = c.Language
+	}
 	if lang != "" {
 		query.Set("language", lang)
 	}
 	u := c.apiURL("GetSeriesByRemoteID.php", query)
 	response := struct {
 		XMLName xml.Name `xml:"Data"`
-		Series  SeriesSummary
+		Series  []SeriesSummary
 	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
+	if err := c.getResponse(ctx, u.String(), &response); err != nil {
 		return nil, err
 	}
 
-	return &response.Series, nil
+	switch len(response.Series) {
+	case 0:
+		return nil, ErrNotFound
+	case 1:
+		return &response.Series[0], nil
+	}
+	return nil, ErrMultipleSeries
 }
 
-// SeriesAllByID gets a single  series with details as well as a list of all the
-// episodes in the series with details.
-func (c *Client) SeriesAllByID(id int, lang string) (*Series, []Episode, error) {
-	u := c.staticAPIURL(fmt.Sprintf("series/%d/all/%s.xml", id, lang))
-	response := struct {
-		XMLName  xml.Name `xml:"Data"`
-		Series   Series
-		Episodes []Episode `xml:"Episode"`
-	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
+// SeriesAllByID gets a single series with details as well as a list of all the
+// episodes in the series with details.  Everything comes from the series'
+// all/<lang>.xml document so only one request is made.  ErrNotFound is
+// returned if the series doesn't exist.
+func (c *Client) SeriesAllByID(ctx context.Context, id int, lang string) (*Series, EpisodeList, error) {
+	ctx = withEndpoint(ctx, "SeriesAllByID")
+	u := c.staticAPIURL(fmt.Sprintf("series/%d/all/%s.xml", id, c.language(lang)))
+	response := seriesDoc{}
+	if err := c.getResponse(ctx, u.String(), &response); err != nil {
 		return nil, nil, err
 	}
+	if response.Series.ID == 0 {
+		return nil, nil, ErrNotFound
+	}
 	return &response.Series, response.Episodes, nil
 }
 
-//TODO: Add SeriesEverything to get the zip and parse it
-//TODO: Add ActorsBySeries
-//TODO: Add BannersBySeries
+// SeasonEpisodes gets the episodes of a single season sorted by episode
+// number.  The API has no per season document so this fetches the series'
+// all/<lang>.xml document like SeriesAllByID and picks out the season.  Set a
+// Cache to avoid fetching the whole series for every season.  ErrNotFound is
+// returned if the season has no episodes.
+func (c *Client) SeasonEpisodes(ctx context.Context, seriesID, season int, lang string) ([]*Episode, error) {
+	_, episodes, err := c.SeriesAllByID(ctx, seriesID, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	seasonEpisodes := episodes.EpisodesInSeason(season)
+	if len(seasonEpisodes) == 0 {
+		return nil, ErrNotFound
+	}
+	return seasonEpisodes, nil
+}
 
-// EpisodeById gets a single episode by the episode ID.
-func (c *Client) EpisodeByID(id int, lang string) (*Episode, error) {
-	u := c.staticAPIURL(fmt.Sprintf("episodes/%d/%s.xml", id, lang))
+// ActorsBySeries gets the cast of a series with their roles and images.
+func (c *Client) ActorsBySeries(ctx context.Context, id int) ([]Actor, error) {
+	ctx = withEndpoint(ctx, "ActorsBySeries")
+	u := c.staticAPIURL(fmt.Sprintf("series/%d/actors.xml", id))
+	response := actorsDoc{}
+	if err := c.getResponse(ctx, u.String(), &response); err != nil {
+		return nil, err
+	}
+	return response.Actors, nil
+}
+
+// BannersBySeries gets all of the artwork available for a series.
+func (c *Client) BannersBySeries(ctx context.Context, id int) (BannerList, error) {
+	ctx = withEndpoint(ctx, "BannersBySeries")
+	u := c.staticAPIURL(fmt.Sprintf("series/%d/banners.xml", id))
+	response := bannersDoc{}
+	if err := c.getResponse(ctx, u.String(), &response); err != nil {
+		return nil, err
+	}
+	return response.Banners, nil
+}
+
+// maxZipSize limits the size of a series zip and of each document in it so a
+// bad response can't exhaust memory.  The largest series are a few MB.
+const maxZipSize = 64 << 20
+
+// FullSeries is everything TheTVDB has on a series as returned by
+// SeriesEverything.
+type FullSeries struct {
+	Series   *Series
+	Episodes EpisodeList
+	Actors   []Actor
+	Banners  BannerList
+}
+
+// SeriesEverything gets a series with its episodes, actors, and banners from
+// the single zip TheTVDB offers for mirroring.  This is one request instead of
+// the three made by SeriesAllByID, ActorsBySeries, and BannersBySeries but the
+// zip is never cached.  ErrNotFound is returned if the series doesn't exist.
+func (c *Client) SeriesEverything(ctx context.Context, id int, lang string) (*FullSeries, error) {
+	ctx = withEndpoint(ctx, "SeriesEverything")
+	lang = c.language(lang)
+	u := c.staticAPIURL(fmt.Sprintf("series/%d/all/%s.zip", id, lang))
+
+	resp, err := c.get(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// zip needs random access so the whole file is read into memory
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxZipSize+1))
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	if len(data) > maxZipSize {
+		return nil, errors.New("tvdb: series zip too large")
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	series := seriesDoc{}
+	actors := actorsDoc{}
+	banners := bannersDoc{}
+	docs := map[string]interface{}{
+		lang + ".xml": &series,
+		"actors.xml":  &actors,
+		"banners.xml": &banners,
+	}
+	for _, f := range zr.File {
+		v, ok := docs[f.Name]
+		if !ok {
+			continue
+		}
+		if err := decodeZipFile(ctx, f, v); err != nil {
+			return nil, fmt.Errorf("tvdb: reading '%s' from series zip: %w", f.Name, err)
+		}
+	}
+
+	if series.Series.ID == 0 {
+		return nil, ErrNotFound
+	}
+	return &FullSeries{
+		Series:   &series.Series,
+		Episodes: series.Episodes,
+		Actors:   actors.Actors,
+		Banners:  banners.Banners,
+	}, nil
+}
+
+// decodeZipFile decodes the XML document f into v reading no more than
+// maxZipSize bytes of it.
+func decodeZipFile(ctx context.Context, f *zip.File, v interface{}) error {
+	if f.UncompressedSize64 > maxZipSize {
+		return errors.New("tvdb: file too large")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return decode(ctx, io.LimitReader(rc, maxZipSize), v)
+}
+
+// EpisodeById gets a single episode by the episode ID.  ErrNotFound is
+// returned if the episode doesn't exist.
+func (c *Client) EpisodeByID(ctx context.Context, id int, lang string) (*Episode, error) {
+	ctx = withEndpoint(ctx, "EpisodeByID")
+	u := c.staticAPIURL(fmt.Sprintf("episodes/%d/%s.xml", id, c.language(lang)))
 	response := struct {
 		XMLName xml.Name `xml:"Data"`
 		Episode Episode
 	}{}
-	if err := c.getResponse(u.String(), &response); err != nil {
+	if err := c.getResponse(ctx, u.String(), &response); err != nil {
 		return nil, err
 	}
+	if response.Episode.ID == 0 {
+		return nil, ErrNotFound
+	}
 	return &response.Episode, nil
 }
 
 // episodeBySeries is a common function to get a single episode from a series
 // ID, series number, and episode number based on a paticular order such as
-// 'dvd' or 'default'
-func (c *Client) episodeBySeries(id int, epNum, lang, order string) (*Episode, error) {
-	u := c.staticAPIURL(fmt.Sprintf("series/%d/%s/%s/%s.xml", id, order, epNum, lang))
+// OrderDVD or OrderDefault.  ErrNotFound is returned if no such episode exists.
+func (c *Client) episodeBySeries(ctx context.Context, id int, epNum, lang string, order Order) (*Episode, error) {
+	ctx = withEndpoint(ctx, "EpisodeBySeries")
+	u := c.staticAPIURL(fmt.Sprintf("series/%d/%s/%s/%s.xml", id, order, epNum, c.language(lang)))
 	resp := struct {
 		XMLName xml.Name `xml:"Data"`
 		Episode Episode
 	}{}
-	if err := c.getResponse(u.String(), &resp); err != nil {
+	if err := c.getResponse(ctx, u.String(), &resp); err != nil {
 		return nil, err
 	}
+	if resp.Episode.ID == 0 {
+		return nil, ErrNotFound
+	}
 	return &resp.Episode, nil
 }
 
 // EpisodeBySeries gets a single episode from the series ID, the season number,
 // and the episode number and uses the default series episode numbering.
-func (c *Client) EpisodeBySeries(id, season, episode int, lang string) (*Episode, error) {
+func (c *Client) EpisodeBySeries(ctx context.Context, id, season, episode int, lang string) (*Episode, error) {
 	epNum := fmt.Sprintf("%d/%d", season, episode)
-	return c.episodeBySeries(id, epNum, lang, "default")
+	return c.episodeBySeries(ctx, id, epNum, lang, OrderDefault)
 }
 
 // EpisodeBySeriesDVD gets a single episode from the series ID, the season number,
 // and the episode number and uses the dvd series episode numbering.
-func (c *Client) EpisodeBySeriesDVD(id, season, episode int, lang string) (*Episode, error) {
+func (c *Client) EpisodeBySeriesDVD(ctx context.Context, id, season, episode int, lang string) (*Episode, error) {
 	epNum := fmt.Sprintf("%d/%d", season, episode)
-	return c.episodeBySeries(id, epNum, lang, "dvd")
+	return c.episodeBySeries(ctx, id, epNum, lang, OrderDVD)
 }
 
 // EpisodeBySeriesAbsolute gets a single episode from the series ID, the season number,
 // and the episode number and uses the absolute series episode numbering.
-func (c *Client) EpisodeBySeriesAbsolute(id, episode int, lang string) (*Episode, error) {
+func (c *Client) EpisodeBySeriesAbsolute(ctx context.Context, id, episode int, lang string) (*Episode, error) {
 	epNum := fmt.Sprintf("%d", episode)
-	return c.episodeBySeries(id, epNum, lang, "absolute")
+	return c.episodeBySeries(ctx, id, epNum, lang, OrderAbsolute)
+}
+
+// EpisodesByAirDate gets all episodes of a series that first aired on the
+// given date.  Only the year, month, and day of airDate are used.
+// ErrNotFound is returned if nothing aired that day.
+// See: https://thetvdb.com/wiki/index.php?title=API:GetEpisodeByAirDate
+func (c *Client) EpisodesByAirDate(ctx context.Context, seriesID int, airDate time.Time, lang string) ([]Episode, error) {
+	ctx = withEndpoint(ctx, "EpisodesByAirDate")
+	query := url.Values{}
+	query.Set("apikey", c.APIKey)
+	query.Set("seriesid", strconv.FormatInt(int64(seriesID), 10))
+	query.Set("airdate", airDate.Format("2006-01-02"))
+	query.Set("language", c.language(lang))
+	u := c.apiURL("GetEpisodeByAirDate.php", query)
+
+	response := struct {
+		XMLName  xml.Name  `xml:"Data"`
+		Episodes []Episode `xml:"Episode"`
+	}{}
+	if err := c.getResponse(ctx, u.String(), &response); err != nil {
+		return nil, err
+	}
+	// No matches are reported as an <Error> element rather than a status code
+	if len(response.Episodes) == 0 {
+		return nil, ErrNotFound
+	}
+	return response.Episodes, nil
+}
+
+// EpisodeByAirDate gets the episode of a series that first aired on the
+// given date.  If several episodes aired that day the first is returned; use
+// EpisodesByAirDate to get all of them.
+func (c *Client) EpisodeByAirDate(ctx context.Context, seriesID int, airDate time.Time, lang string) (*Episode, error) {
+	episodes, err := c.EpisodesByAirDate(ctx, seriesID, airDate, lang)
+	if err != nil {
+		return nil, err
+	}
+	return &episodes[0], nil
 }
 
 // userFav is the internal function for UserFav, UserFavAdd, and UserFavRemove
 // since they all use the same API.
-func (c *Client) userFavs(accountID, actionType string, seriesID int) ([]int, error) {
+func (c *Client) userFavs(ctx context.Context, accountID, actionType string, seriesID int) ([]int, error) {
+	ctx = withEndpoint(ctx, "UserFavorites")
 	query := url.Values{}
 	query.Set("accountid", accountID)
 
@@ -535,7 +1907,7 @@ func (c *Client) userFavs(accountID, actionType string, seriesID int) ([]int, er
 		Series  []int
 	}{}
 
-	if err := c.getResponse(u.String(), data); err != nil {
+	if err := c.fetchResponse(ctx, u.String(), data, nil); err != nil {
 		return nil, err
 	}
 	return data.Series, nil
@@ -546,23 +1918,23 @@ func (c *Client) userFavs(accountID, actionType string, seriesID int) ([]int, er
 //
 // Note: the accountID here is not the username of the user but rather a special
 // accountID.  Users can retrive thier accountIDs from thier user info page @
-// http://thetvdb.com/?tab=userinfo.
-func (c *Client) UserFavs(accountID string) ([]int, error) {
-	return c.userFavs(accountID, "", 0)
+// https://thetvdb.com/?tab=userinfo.
+func (c *Client) UserFavs(ctx context.Context, accountID string) ([]int, error) {
+	return c.userFavs(ctx, accountID, "", 0)
 }
 
 // UserFavAdd will add a series by the series id to a users favorites. It will
 // return the modified list. See UserFavs for information on how to use the
 // accountID.
-func (c *Client) UserFavAdd(accountID string, seriesID int) ([]int, error) {
-	return c.userFavs(accountID, "add", seriesID)
+func (c *Client) UserFavAdd(ctx context.Context, accountID string, seriesID int) ([]int, error) {
+	return c.userFavs(ctx, accountID, "add", seriesID)
 }
 
 // UserFavRemove will delete a series by the series id from the users
 // favorites.  It will return the modified list.  See UserFavs for information
 // on how to use the accountID.
-func (c *Client) UserFavRemove(accountID string, seriesID int) ([]int, error) {
-	return c.userFavs(accountID, "remove", seriesID)
+func (c *Client) UserFavRemove(ctx context.Context, accountID string, seriesID int) ([]int, error) {
+	return c.userFavs(ctx, accountID, "remove", seriesID)
 }
 
 // ratingResult is used in multiple places so it's it defined as the xml return for
@@ -573,7 +1945,8 @@ type ratingResult struct {
 }
 
 // userRatings is a common function used for all user rating functions.
-func (c *Client) userRatings(accountID string, seriesID int) (*ratingResult, error) {
+func (c *Client) userRatings(ctx context.Context, accountID string, seriesID int) (*ratingResult, error) {
+	ctx = withEndpoint(ctx, "UserRatings")
 	query := url.Values{}
 
 	query.Set("apikey", c.APIKey) //Love the consistency of this API
@@ -583,7 +1956,7 @@ func (c *Client) userRatings(accountID string, seriesID int) (*ratingResult, err
 	}
 	u := c.apiURL("GetRatingsForUser.php", query)
 	result := &ratingResult{}
-	if err := c.getResponse(u.String(), result); err != nil {
+	if err := c.fetchResponse(ctx, u.String(), result, nil); err != nil {
 		return nil, err
 	}
 
@@ -591,8 +1964,8 @@ func (c *Client) userRatings(accountID string, seriesID int) (*ratingResult, err
 }
 
 // UserRatings will get the ratings for all series a user has rated.
-func (c *Client) UserRatings(accountID string) ([]*Rating, error) {
-	result, err := c.userRatings(accountID, 0)
+func (c *Client) UserRatings(ctx context.Context, accountID string) ([]*Rating, error) {
+	result, err := c.userRatings(ctx, accountID, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -603,8 +1976,8 @@ func (c *Client) UserRatings(accountID string) ([]*Rating, error) {
 // UserRatingsSeries will get the user raiting for a single series by the
 // series ID and return the rating for that series as well as all episodes
 // for that series.
-func (c *Client) UserRatingsSeries(accountID string, seriesID int) (*Rating, []*Rating, error) {
-	result, err := c.userRatings(accountID, seriesID)
+func (c *Client) UserRatingsSeries(ctx context.Context, accountID string, seriesID int) (*Rating, []*Rating, error) {
+	result, err := c.userRatings(ctx, accountID, seriesID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -614,7 +1987,8 @@ func (c *Client) UserRatingsSeries(accountID string, seriesID int) (*Rating, []*
 
 // setUserRating is a common function for both SetUserRatingSeries and
 // SetUserRatingEpisode since they utilize the same API.
-func (c *Client) setUserRating(accountID, itemType string, itemID, rating int) error {
+func (c *Client) setUserRating(ctx context.Context, accountID, itemType string, itemID, rating int) error {
+	ctx = withEndpoint(ctx, "SetUserRating")
 	if rating < 0 || rating > 10 {
 		return fmt.Errorf("Rating must be between 0 and 10 inclusive")
 	}
@@ -627,23 +2001,24 @@ func (c *Client) setUserRating(accountID, itemType string, itemID, rating int) e
 	u := c.apiURL("User_Rating.php", query)
 
 	// This API just returns the global rating.  Lets just ignore it
-	return c.getResponse(u.String(), nil)
+	return c.fetchResponse(ctx, u.String(), nil, nil)
 }
 
 // SetUserRatingSeries will update or set a users rating for a series by series ID
-func (c *Client) SetUserRatingSeries(accountID string, seriesID, rating int) error {
-	return c.setUserRating(accountID, "series", seriesID, rating)
+func (c *Client) SetUserRatingSeries(ctx context.Context, accountID string, seriesID, rating int) error {
+	return c.setUserRating(ctx, accountID, "series", seriesID, rating)
 }
 
 // SetUserRatingEp will update or set a users rating for an episode by episode
 // ID.
-func (c *Client) SetUserRatingEp(accountID string, epID, rating int) error {
-	return c.setUserRating(accountID, "episode", epID, rating)
+func (c *Client) SetUserRatingEp(ctx context.Context, accountID string, epID, rating int) error {
+	return c.setUserRating(ctx, accountID, "episode", epID, rating)
 }
 
 // UserLang will return the prefered language for a user with a given account
 // id.
-func (c *Client) UserLang(accountID string) (*Language, error) {
+func (c *Client) UserLang(ctx context.Context, accountID string) (*Language, error) {
+	ctx = withEndpoint(ctx, "UserLang")
 	u := c.apiURL("User_PreferredLanguage.php", url.Values{
 		"accountid": []string{accountID},
 	})
@@ -651,7 +2026,7 @@ func (c *Client) UserLang(accountID string) (*Language, error) {
 	resp := &struct {
 		Lang Language `xml:"Language"`
 	}{}
-	if err := c.getResponse(u.String(), resp); err != nil {
+	if err := c.fetchResponse(ctx, u.String(), resp, nil); err != nil {
 		return nil, err
 	}
 
@@ -1,17 +1,28 @@
 package tvdb
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/kylelemons/godebug/pretty"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -86,7 +97,7 @@ func TestLanguages(t *testing.T) {
 	handler = newFileHandler("testdata/languages.xml")
 	mux.Handle(fmt.Sprintf("/api/%s/languages.xml", apiKey), handler)
 
-	langs, err := client.Languages()
+	langs, err := client.Languages(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -121,7 +132,7 @@ func TestSearchSeries(t *testing.T) {
 		handler.ServeHTTP(w, r)
 	})
 
-	series, err := client.SearchSeries("The Simpsons", "en")
+	series, err := client.SearchSeries(context.Background(), "The Simpsons", "en")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -154,7 +165,7 @@ func TestSeriesByID(t *testing.T) {
 	handler = newFileHandler("testdata/series_71663_en.xml")
 	mux.Handle(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), handler)
 
-	series, err := client.SeriesByID(71663, "en")
+	series, err := client.SeriesByID(context.Background(), 71663, "en")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -203,7 +214,7 @@ func TestSeriesByRemoteID(t *testing.T) {
 		handler.ServeHTTP(w, r)
 	})
 
-	series, err := client.SeriesByRemoteID(IMDB, "tt0096697", "en")
+	series, err := client.SeriesByRemoteID(context.Background(), IMDB, "tt0096697", "en")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -231,7 +242,7 @@ func TestSeriesAllByID(t *testing.T) {
 	handler = newFileHandler("testdata/series_71663_all_en.xml")
 	mux.Handle(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), handler)
 
-	series, episodes, err := client.SeriesAllByID(71663, "en")
+	series, episodes, err := client.SeriesAllByID(context.Background(), 71663, "en")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -263,7 +274,7 @@ func TestSeriesAllByID(t *testing.T) {
 	}
 
 	episodeWant := Episode{
-		ID: 4350173,
+		ID:                    4350173,
 		CombinedEpisodeNumber: "1",
 		CombinedSeason:        0,
 		DVDEpisodeNumber:      "",
@@ -308,13 +319,13 @@ func TestEpisodeByID(t *testing.T) {
 	handler = newFileHandler("testdata/episodes_4350173_en.xml")
 	mux.Handle(fmt.Sprintf("/api/%s/episodes/4350173/en.xml", apiKey), handler)
 
-	episode, err := client.EpisodeByID(4350173, "en")
+	episode, err := client.EpisodeByID(context.Background(), 4350173, "en")
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	want := &Episode{
-		ID: 4350173,
+		ID:                    4350173,
 		CombinedEpisodeNumber: "",
 		CombinedSeason:        0,
 		DVDEpisodeNumber:      "",
@@ -367,9 +378,9 @@ func TestEpisodeBySeries(t *testing.T) {
 	mux.Handle(fmt.Sprintf("/api/%s/series/71663/absolute/1/en.xml", apiKey), absHandler)
 
 	funcMap := map[string]func() (*Episode, error){
-		"default":  func() (*Episode, error) { return client.EpisodeBySeries(71663, 1, 1, "en") },
-		"dvd":      func() (*Episode, error) { return client.EpisodeBySeriesDVD(71663, 1, 1, "en") },
-		"absolute": func() (*Episode, error) { return client.EpisodeBySeriesAbsolute(71663, 1, "en") },
+		"default":  func() (*Episode, error) { return client.EpisodeBySeries(context.Background(), 71663, 1, 1, "en") },
+		"dvd":      func() (*Episode, error) { return client.EpisodeBySeriesDVD(context.Background(), 71663, 1, 1, "en") },
+		"absolute": func() (*Episode, error) { return client.EpisodeBySeriesAbsolute(context.Background(), 71663, 1, "en") },
 	}
 
 	for order, f := range funcMap {
@@ -379,7 +390,7 @@ func TestEpisodeBySeries(t *testing.T) {
 		}
 
 		want := &Episode{
-			ID: 55452,
+			ID:                    55452,
 			CombinedEpisodeNumber: "",
 			CombinedSeason:        0,
 			DVDEpisodeNumber:      "1.0",
@@ -500,7 +511,7 @@ func TestUserFavs(t *testing.T) {
 		260315,
 	}
 
-	got, err := client.UserFavs("D4FDF436DA8BD059")
+	got, err := client.UserFavs(context.Background(), "D4FDF436DA8BD059")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -509,7 +520,7 @@ func TestUserFavs(t *testing.T) {
 		t.Errorf("userFavs does not match.\n%s", pretty.Compare(got, want))
 	}
 
-	gotAdd, err := client.UserFavAdd("D4FDF436DA8BD059", 80348)
+	gotAdd, err := client.UserFavAdd(context.Background(), "D4FDF436DA8BD059", 80348)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -520,7 +531,7 @@ func TestUserFavs(t *testing.T) {
 		t.Errorf("UserFavs does not match.\n%s", pretty.Compare(gotAdd, newWant))
 	}
 
-	gotRemove, err := client.UserFavRemove("D4FDF436DA8BD059", 80348)
+	gotRemove, err := client.UserFavRemove(context.Background(), "D4FDF436DA8BD059", 80348)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -530,3 +541,1511 @@ func TestUserFavs(t *testing.T) {
 	}
 
 }
+
+func TestContextCancel(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Languages><Language>`)
+		w.(http.Flusher).Flush()
+
+		// Cancel mid-download and hold the body open until the client leaves
+		cancel()
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	})
+
+	_, err := client.Languages(ctx)
+	close(done)
+	if err != context.Canceled {
+		t.Errorf("Expected '%v' got '%v'", context.Canceled, err)
+	}
+}
+
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	transport := &countingTransport{}
+	client := setup()
+	defer server.Close()
+	WithHTTPClient(&http.Client{Transport: transport})(client)
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 1 {
+		t.Errorf("Expected '1' request through custom client got '%d'", transport.requests)
+	}
+
+	// A nil client falls back to http.DefaultClient
+	WithHTTPClient(nil)(client)
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fileDoer is a Doer that answers every request with the contents of a file.
+type fileDoer struct {
+	file     string
+	requests []string
+}
+
+func (d *fileDoer) Do(r *http.Request) (*http.Response, error) {
+	d.requests = append(d.requests, r.URL.String())
+	f, err := os.Open(d.file)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: f, Header: http.Header{}}, nil
+}
+
+func TestWithDoer(t *testing.T) {
+	doer := &fileDoer{file: "testdata/languages.xml"}
+	client := NewClient(apiKey, WithDoer(doer))
+
+	langs, err := client.Languages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(langs) == 0 {
+		t.Error("Expected languages from the canned response")
+	}
+
+	want := []string{fmt.Sprintf("https://thetvdb.com/api/%s/languages.xml", apiKey)}
+	if !reflect.DeepEqual(doer.requests, want) {
+		t.Errorf("Expected requests '%v' got '%v'", want, doer.requests)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "<html><body>Not Found</body></html>")
+	})
+
+	_, err := client.SeriesByID(context.Background(), 1, "en")
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatalf("Expected *HTTPError got '%T' (%v)", err, err)
+	}
+	if httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status code '%d' got '%d'", http.StatusNotFound, httpErr.StatusCode)
+	}
+}
+
+func TestClientLanguage(t *testing.T) {
+	client := setup()
+	defer teardown()
+	client.Language = "de"
+
+	handler = newFileHandler("testdata/series_71663_en.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/de.xml", apiKey), handler)
+
+	if _, err := client.SeriesByID(context.Background(), 71663, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBaseURLPrefix(t *testing.T) {
+	client := setup()
+	defer teardown()
+	client.BaseURL.Path = "/mirror/"
+
+	handler = newFileHandler("testdata/languages.xml")
+	mux.Handle(fmt.Sprintf("/mirror/api/%s/languages.xml", apiKey), handler)
+
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNullFloat64(t *testing.T) {
+	tests := map[string]nullFloat64{
+		"<Rating></Rating>":    NulFloat64,
+		"<Rating> </Rating>":   NulFloat64,
+		"<Rating>7.2</Rating>": NullFloat64(7.2),
+		"<Rating>0</Rating>":   NullFloat64(0),
+	}
+
+	for doc, want := range tests {
+		var got nullFloat64
+		if err := xml.Unmarshal([]byte(doc), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Parsing '%s' expected '%v' got '%v'", doc, want, got)
+		}
+	}
+}
+
+func TestNullInt(t *testing.T) {
+	tests := map[string]nullInt{
+		"<Runtime></Runtime>":   NulInt,
+		"<Runtime> </Runtime>":  NulInt,
+		"<Runtime>30</Runtime>": NullInt(30),
+		"<Runtime>0</Runtime>":  NullInt(0),
+	}
+
+	for doc, want := range tests {
+		var got nullInt
+		if err := xml.Unmarshal([]byte(doc), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Parsing '%s' expected '%v' got '%v'", doc, want, got)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.MaxRetries = 2
+	client.RetryBackoff = time.Millisecond
+
+	requests := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected '3' requests got '%d'", requests)
+	}
+
+	// Client errors are not retried
+	requests = 0
+	if _, err := client.SeriesByID(context.Background(), 1, "en"); err == nil {
+		t.Error("Expected an error for a 404 response")
+	}
+	if requests != 1 {
+		t.Errorf("Expected '1' request got '%d'", requests)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&HTTPError{StatusCode: http.StatusServiceUnavailable}, true},
+		{&HTTPError{StatusCode: http.StatusTooManyRequests}, true},
+		{&HTTPError{StatusCode: http.StatusNotFound}, false},
+		{&url.Error{Op: "Get", URL: "https://thetvdb.com", Err: io.ErrUnexpectedEOF}, true},
+		{&url.Error{Op: "parse", URL: "://", Err: errors.New("missing protocol scheme")}, false},
+		{context.Canceled, false},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), false},
+		{gzip.ErrHeader, false},
+		{errors.New("tvdb: something else"), false},
+	}
+	for _, test := range tests {
+		if got := retryable(test.err); got != test.want {
+			t.Errorf("Error '%v' expected '%v' got '%v'", test.err, test.want, got)
+		}
+	}
+}
+
+func TestRetryDecodeError(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.MaxRetries = 2
+	client.RetryBackoff = time.Millisecond
+
+	requests := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Encoding", "gzip")
+		io.WriteString(w, "not gzip")
+	})
+
+	if _, err := client.Languages(context.Background()); err == nil {
+		t.Error("Expected an error for a corrupt gzip body")
+	}
+	if requests != 1 {
+		t.Errorf("Expected '1' request got '%d'", requests)
+	}
+}
+
+func TestBackoffLimit(t *testing.T) {
+	for _, base := range []time.Duration{0, time.Millisecond, time.Hour} {
+		c := &Client{RetryBackoff: base}
+		for _, attempt := range []int{0, 22, 35, 64, 1000} {
+			if d := c.backoff(attempt); d <= 0 || d > maxRetryBackoff {
+				t.Errorf("Backoff '%s' attempt '%d' expected up to '%s' got '%s'", base, attempt, maxRetryBackoff, d)
+			}
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	// An empty bucket that never refills blocks until the context is done
+	client.RateLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+	client.RateLimiter.Allow()
+
+	requests := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.Languages(ctx); err == nil {
+		t.Error("Expected rate limited request to fail")
+	}
+	if requests != 0 {
+		t.Errorf("Expected '0' requests got '%d'", requests)
+	}
+}
+
+func TestEpisodeByIDNotFound(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/episodes/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/episodes/2/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Data></Data>`)
+	})
+
+	for _, id := range []int{1, 2} {
+		if _, err := client.EpisodeByID(context.Background(), id, "en"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Episode '%d' expected '%v' got '%v'", id, ErrNotFound, err)
+		}
+	}
+}
+
+func TestSeriesByIDNotFound(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Data></Data>`)
+	})
+
+	series, err := client.SeriesByID(context.Background(), 1, "en")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+	if series != nil {
+		t.Errorf("Expected no series got '%v'", series)
+	}
+}
+
+func TestSeriesAllByIDNotFound(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/all/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Data></Data>`)
+	})
+
+	series, episodes, err := client.SeriesAllByID(context.Background(), 1, "en")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+	if series != nil || episodes != nil {
+		t.Errorf("Expected no series or episodes got '%v' and '%v'", series, episodes)
+	}
+}
+
+func TestEpisodeBySeriesNotFound(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/default/99/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	if _, err := client.EpisodeBySeries(context.Background(), 71663, 99, 1, "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestEpisodeByAirDate(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler(`testdata/GetEpisodeByAirDate.php?seriesid=71663&airdate=1989-12-17&language=en`)
+	mux.HandleFunc("/api/GetEpisodeByAirDate.php", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("airdate") != "1989-12-17" {
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Data><Error>No Results from SP</Error></Data>`)
+			return
+		}
+		testFormValues(t, r, values{
+			"apikey":   apiKey,
+			"seriesid": "71663",
+			"airdate":  "1989-12-17",
+			"language": "en",
+		})
+		handler.ServeHTTP(w, r)
+	})
+
+	episode, err := client.EpisodeByAirDate(context.Background(), 71663, time.Date(1989, time.December, 17, 20, 0, 0, 0, time.UTC), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if episode.ID != 55452 {
+		t.Errorf("Expected episode '55452' got '%d'", episode.ID)
+	}
+
+	_, err = client.EpisodeByAirDate(context.Background(), 71663, time.Date(1989, time.December, 18, 0, 0, 0, 0, time.UTC), "en")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestActorsBySeries(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_actors.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/actors.xml", apiKey), handler)
+
+	actors, err := client.ActorsBySeries(context.Background(), 71663)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Actor{
+		{ID: 27747, Name: "Dan Castellaneta", Role: "Homer Simpson / Abraham Simpson / Krusty the Clown", SortOrder: 0, ImagePath: "actors/27747.jpg"},
+		{ID: 27750, Name: "Julie Kavner", Role: "Marge Simpson / Patty Bouvier / Selma Bouvier", SortOrder: 1, ImagePath: "actors/27750.jpg"},
+		{ID: 27751, Name: "Nancy Cartwright", Role: "Bart Simpson / Nelson Muntz / Ralph Wiggum", SortOrder: 2, ImagePath: ""},
+	}
+
+	if !reflect.DeepEqual(actors, want) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(want, actors))
+	}
+}
+
+func TestBannersBySeries(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/series_71663_banners.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/series/71663/banners.xml", apiKey), handler)
+
+	banners, err := client.BannersBySeries(context.Background(), 71663)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(banners) != 6 {
+		t.Errorf("Incorrect number of banners. Expected '6' got '%d'", len(banners))
+	}
+
+	want := BannerList{
+		{
+			ID:            38119,
+			BannerPath:    "fanart/original/71663-1.jpg",
+			BannerType:    BannerFanart,
+			BannerType2:   "1920x1080",
+			Colors:        pipeList{"217,177,118", "59,40,68", "214,192,205"},
+			Language:      "en",
+			Rating:        NullFloat64(7.6667),
+			RatingCount:   NullInt(12),
+			Season:        NulInt,
+			ThumbnailPath: "_cache/fanart/original/71663-1.jpg",
+		},
+		{
+			ID:          23811,
+			BannerPath:  "posters/71663-1.jpg",
+			BannerType:  BannerPoster,
+			BannerType2: "680x1000",
+			Language:    "en",
+			Rating:      NullFloat64(8),
+			RatingCount: NullInt(4),
+			Season:      NulInt,
+		},
+		{
+			ID:          3285,
+			BannerPath:  "seasons/71663-1.jpg",
+			BannerType:  BannerSeason,
+			BannerType2: "season",
+			Language:    "en",
+			Rating:      NulFloat64,
+			RatingCount: NullInt(0),
+			Season:      NullInt(1),
+		},
+	}
+
+	if !reflect.DeepEqual(banners[:3], want) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(want, banners[:3]))
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var got string
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got != DefaultUserAgent {
+		t.Errorf("Expected User-Agent '%s' got '%s'", DefaultUserAgent, got)
+	}
+
+	client.UserAgent = "myapp/2.0"
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got != "myapp/2.0" {
+		t.Errorf("Expected User-Agent 'myapp/2.0' got '%s'", got)
+	}
+}
+
+func TestSetUserRating(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/User_Rating.php", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{
+			"accountid": testUser,
+			"itemtype":  "series",
+			"itemid":    "71663",
+			"rating":    "9",
+		})
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Data><Series><Rating>9.0</Rating></Series></Data>`)
+	})
+
+	if err := client.SetUserRatingSeries(context.Background(), testUser, 71663, 9); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetUserRatingSeries(context.Background(), testUser, 71663, 11); err == nil {
+		t.Error("Expected an error for an out of range rating")
+	}
+}
+
+func TestSeriesByRemoteIDErrors(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/GetSeriesByRemoteID.php", func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("zap2it") {
+		case "EP00000000":
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Data></Data>`)
+		default:
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Data><Series><id>1</id></Series><Series><id>2</id></Series></Data>`)
+		}
+	})
+
+	tests := map[string]error{
+		"EP00000000": ErrNotFound,
+		"EP00018693": ErrMultipleSeries,
+	}
+	for id, want := range tests {
+		if _, err := client.SeriesByRemoteID(context.Background(), Zap2it, id, "en"); !errors.Is(err, want) {
+			t.Errorf("Remote ID '%s' expected '%v' got '%v'", id, want, err)
+		}
+	}
+}
+
+func TestPipeList(t *testing.T) {
+	tests := map[string]pipeList{
+		"<Genre></Genre>":                      {},
+		"<Genre> </Genre>":                     {},
+		"<Genre>|</Genre>":                     {},
+		"<Genre>Comedy</Genre>":                {"Comedy"},
+		"<Genre>|Animation|Comedy|</Genre>":    {"Animation", "Comedy"},
+		"<Genre>|Animation|| Comedy |</Genre>": {"Animation", "Comedy"},
+	}
+
+	for doc, want := range tests {
+		var got pipeList
+		if err := xml.Unmarshal([]byte(doc), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Parsing '%s' expected '%#v' got '%#v'", doc, want, got)
+		}
+	}
+}
+
+func TestPipeListMarshal(t *testing.T) {
+	tests := map[string]pipeList{
+		"<Series><Genre></Genre></Series>":                   {},
+		"<Series><Genre>|Comedy|</Genre></Series>":           {"Comedy"},
+		"<Series><Genre>|Animation|Comedy|</Genre></Series>": {"Animation", "Comedy"},
+	}
+
+	for want, list := range tests {
+		got, err := xml.Marshal(struct {
+			XMLName xml.Name `xml:"Series"`
+			Genre   pipeList `xml:"Genre"`
+		}{Genre: list})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("Marshalling '%#v' expected '%s' got '%s'", list, want, got)
+		}
+	}
+}
+
+func TestEpisodeMarshalJSON(t *testing.T) {
+	ep := Episode{
+		ID:          55452,
+		EpisodeName: "Simpsons Roasting on an Open Fire",
+		FirstAired:  Date(1989, time.December, 17),
+		GuestStars:  pipeList{"Christopher Collins"},
+		Rating:      NullFloat64(7.2),
+		RatingCount: NulInt,
+		ThumbAdded:  NullDateTime,
+	}
+
+	b, err := json.Marshal(ep)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"id":           float64(55452),
+		"episode_name": "Simpsons Roasting on an Open Fire",
+		"first_aired":  "1989-12-17",
+		"guest_stars":  []interface{}{"Christopher Collins"},
+		"director":     []interface{}{},
+		"rating":       7.2,
+		"rating_count": nil,
+		"thumb_added":  nil,
+	}
+	for k, v := range want {
+		if !reflect.DeepEqual(got[k], v) {
+			t.Errorf("Expected '%s' to be '%#v' got '%#v'", k, v, got[k])
+		}
+	}
+}
+
+func TestStringers(t *testing.T) {
+	tests := []struct {
+		v    fmt.Stringer
+		want string
+	}{
+		{Series{ID: 78874, Name: "Firefly", FirstAired: Date(2002, time.September, 20)}, "Firefly (2002) [78874]"},
+		{Series{ID: 78874, Name: "Firefly"}, "Firefly [78874]"},
+		{Series{ID: 78874}, "Unknown [78874]"},
+		{Episode{SeasonNumber: 1, EpisodeNumber: 3, EpisodeName: "Bushwhacked"}, "S01E03 — Bushwhacked"},
+		{Episode{SeasonNumber: 12, EpisodeNumber: 104}, "S12E104"},
+	}
+
+	for _, test := range tests {
+		if got := test.v.String(); got != test.want {
+			t.Errorf("Expected '%s' got '%s'", test.want, got)
+		}
+	}
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	c := NewClient(apiKey)
+	if hc, ok := c.HTTPClient.(*http.Client); !ok || hc.Timeout != DefaultTimeout {
+		t.Errorf("Expected a client with timeout '%s' got '%#v'", DefaultTimeout, c.HTTPClient)
+	}
+
+	hc := &http.Client{Timeout: time.Second}
+	if c := NewClient(apiKey, WithHTTPClient(hc)); c.HTTPClient != hc {
+		t.Errorf("Expected the client given to WithHTTPClient got '%#v'", c.HTTPClient)
+	}
+}
+
+func TestDefaultTransport(t *testing.T) {
+	c := NewClient(apiKey)
+	hc, ok := c.HTTPClient.(*http.Client)
+	if !ok {
+		t.Fatalf("Expected an *http.Client got '%#v'", c.HTTPClient)
+	}
+	transport, ok := hc.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport got '%#v'", hc.Transport)
+	}
+	if transport.MaxIdleConnsPerHost < DefaultConcurrency {
+		t.Errorf("Expected at least '%d' idle connections per host got '%d'", DefaultConcurrency, transport.MaxIdleConnsPerHost)
+	}
+	if DefaultTransport() == DefaultTransport() {
+		t.Errorf("Expected a new transport from every call")
+	}
+}
+
+func TestNewClientOptions(t *testing.T) {
+	u, _ := url.Parse("http://mirror.example.com/tvdb")
+	c := NewClient(apiKey,
+		WithLanguage("de"),
+		WithBaseURL(u),
+		WithUserAgent("test-agent/1.0"),
+		WithRateLimit(2, 1),
+	)
+
+	if c.Language != "de" {
+		t.Errorf("Expected language 'de' got '%s'", c.Language)
+	}
+	if c.BaseURL != u {
+		t.Errorf("Expected base URL '%s' got '%s'", u, c.BaseURL)
+	}
+	if c.UserAgent != "test-agent/1.0" {
+		t.Errorf("Expected user agent 'test-agent/1.0' got '%s'", c.UserAgent)
+	}
+	if c.RateLimiter == nil || c.RateLimiter.Limit() != 2 || c.RateLimiter.Burst() != 1 {
+		t.Errorf("Expected a rate limiter of '2' requests per second with burst '1' got '%#v'", c.RateLimiter)
+	}
+}
+
+func TestTrimmedProductionCode(t *testing.T) {
+	if got := (Episode{ProductionCode: " 7G08\n"}).TrimmedProductionCode(); got != "7G08" {
+		t.Errorf("Expected '7G08' got '%s'", got)
+	}
+}
+
+func TestSeriesStatus(t *testing.T) {
+	tests := []struct {
+		status     string
+		ended      bool
+		continuing bool
+	}{
+		{"Ended", true, false},
+		{"ended ", true, false},
+		{"Continuing", false, true},
+		{"CONTINUING", false, true},
+		{"", false, false},
+		{"On Hiatus", false, false},
+	}
+
+	for _, test := range tests {
+		s := Series{Status: test.status}
+		if got := s.IsEnded(); got != test.ended {
+			t.Errorf("Expected IsEnded for '%s' to be '%t' got '%t'", test.status, test.ended, got)
+		}
+		if got := s.IsContinuing(); got != test.continuing {
+			t.Errorf("Expected IsContinuing for '%s' to be '%t' got '%t'", test.status, test.continuing, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+	mux.HandleFunc("/api/BADKEY/languages.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, "<html><body>Invalid API key</body></html>")
+	})
+	mux.HandleFunc("/api/MISSINGKEY/languages.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	if err := client.Validate(context.Background()); err != nil {
+		t.Errorf("Expected a valid key got '%v'", err)
+	}
+
+	for _, key := range []string{"", " ", "BADKEY", "MISSINGKEY"} {
+		client.APIKey = key
+		if err := client.Validate(context.Background()); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("Key '%s' expected '%v' got '%v'", key, ErrInvalidAPIKey, err)
+		}
+	}
+
+	mux.HandleFunc("/api/LIMITEDKEY/languages.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	client.APIKey = "LIMITEDKEY"
+	err := client.Validate(context.Background())
+	var httpErr *HTTPError
+	if errors.Is(err, ErrInvalidAPIKey) || !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected the '%d' HTTPError got '%v'", http.StatusTooManyRequests, err)
+	}
+}
+
+func TestOnRequest(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.MaxRetries = 1
+	client.RetryBackoff = time.Millisecond
+
+	requests := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+
+	var errs []error
+	client.OnRequest = func(method, url string, dur time.Duration, err error) {
+		if method != "GET" {
+			t.Errorf("Expected method 'GET' got '%s'", method)
+		}
+		if want := fmt.Sprintf("%s/api/%s/languages.xml", server.URL, apiKey); url != want {
+			t.Errorf("Expected url '%s' got '%s'", want, url)
+		}
+		errs = append(errs, err)
+	}
+
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("Expected '2' calls got '%d'", len(errs))
+	}
+	if errs[0] == nil || errs[1] != nil {
+		t.Errorf("Expected the first attempt to fail and the retry to succeed got '%v'", errs)
+	}
+}
+
+type recordingObserver struct {
+	started  []string
+	finished []string
+	errs     []error
+}
+
+func (o *recordingObserver) RequestStarted(endpoint string) {
+	o.started = append(o.started, endpoint)
+}
+
+func (o *recordingObserver) RequestFinished(endpoint string, dur time.Duration, err error) {
+	o.finished = append(o.finished, endpoint)
+	o.errs = append(o.errs, err)
+}
+
+func TestObserver(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.MaxRetries = 1
+	client.RetryBackoff = time.Millisecond
+
+	requests := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeFile(w, r, "testdata/series_71663_en.xml")
+	})
+
+	observer := &recordingObserver{}
+	client.Observer = observer
+
+	if _, err := client.SeriesByID(context.Background(), 71663, "en"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"SeriesByID", "SeriesByID"}
+	if !reflect.DeepEqual(observer.started, want) {
+		t.Errorf("Expected started '%v' got '%v'", want, observer.started)
+	}
+	if !reflect.DeepEqual(observer.finished, want) {
+		t.Errorf("Expected finished '%v' got '%v'", want, observer.finished)
+	}
+	if len(observer.errs) != 2 || observer.errs[0] == nil || observer.errs[1] != nil {
+		t.Errorf("Expected the first attempt to fail and the retry to succeed got '%v'", observer.errs)
+	}
+}
+
+func TestGzip(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != "gzip" {
+			t.Errorf("Expected Accept-Encoding 'gzip' got '%s'", got)
+		}
+		b, err := ioutil.ReadFile("testdata/languages.xml")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write(b)
+		zw.Close()
+	})
+
+	langs, err := client.Languages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(langs) == 0 {
+		t.Error("Expected languages from the compressed response")
+	}
+}
+
+func TestEpisodeGuestStars(t *testing.T) {
+	doc := `<Episode><GuestStars>|Christopher Collins||Pamela Hayden|</GuestStars><Director>David Silverman</Director></Episode>`
+
+	var ep Episode
+	if err := xml.Unmarshal([]byte(doc), &ep); err != nil {
+		t.Fatal(err)
+	}
+	if want := (pipeList{"Christopher Collins", "Pamela Hayden"}); !reflect.DeepEqual(ep.GuestStars, want) {
+		t.Errorf("Expected guest stars '%#v' got '%#v'", want, ep.GuestStars)
+	}
+	if want := (pipeList{"David Silverman"}); !reflect.DeepEqual(ep.Director, want) {
+		t.Errorf("Expected director '%#v' got '%#v'", want, ep.Director)
+	}
+}
+
+func TestSearchSeriesYear(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/GetSeries.php?seriesname=The%20Simpsons")
+	})
+
+	tests := []struct {
+		year, tolerance int
+		want            []int
+	}{
+		{1989, 0, []int{71663}},
+		{1991, 1, []int{}},
+		{1991, 2, []int{71663}},
+	}
+
+	for _, test := range tests {
+		series, err := client.SearchSeriesYear(context.Background(), "The Simpsons", test.year, test.tolerance, "en")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []int{}
+		for _, s := range series {
+			got = append(got, s.ID)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Year '%d' with tolerance '%d' expected '%v' got '%v'", test.year, test.tolerance, test.want, got)
+		}
+	}
+}
+
+func TestSeriesDiff(t *testing.T) {
+	a := &Series{
+		ID:          71663,
+		Name:        "The Simpsons",
+		Genre:       pipeList{"Animation", "Comedy"},
+		Actors:      pipeList{},
+		FirstAired:  Date(1989, time.December, 17),
+		Rating:      NullFloat64(9.0),
+		LastUpdated: unixTime{time.Unix(1422643901, 0)},
+	}
+	b := *a
+	b.Actors = nil
+	b.LastUpdated = unixTime{time.Unix(1424174914, 0)}
+
+	if !a.Equal(&b) {
+		t.Errorf("Expected series to be equal got diff '%v'", a.Diff(&b))
+	}
+
+	b.Status = "Ended"
+	b.Genre = pipeList{"Animation"}
+	b.Rating = NullFloat64(8.9)
+	if a.Equal(&b) {
+		t.Error("Expected series to differ")
+	}
+	if want, got := []string{"Genre", "Rating", "Status"}, a.Diff(&b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected diff '%v' got '%v'", want, got)
+	}
+
+	if a.Equal(nil) || !(*Series)(nil).Equal(nil) {
+		t.Error("Expected only nil to equal nil")
+	}
+}
+
+func TestUnixTime(t *testing.T) {
+	tests := map[string]time.Time{
+		"<lastupdated>1306809485</lastupdated>": time.Unix(1306809485, 0).UTC(),
+		"<lastupdated></lastupdated>":           {},
+		"<lastupdated> </lastupdated>":          {},
+		"<lastupdated>0</lastupdated>":          {},
+	}
+
+	for doc, want := range tests {
+		var got unixTime
+		if err := xml.Unmarshal([]byte(doc), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want) || got.IsZero() != want.IsZero() {
+			t.Errorf("Parsing '%s' expected '%s' got '%s'", doc, want, got)
+		}
+	}
+}
+
+func TestSearchSeriesPage(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/GetSeries.php?seriesname=The%20Simpsons")
+	})
+
+	tests := []struct {
+		offset, limit int
+		want          []int
+	}{
+		{0, 1, []int{71663}},
+		{1, 1, []int{153221}},
+		{0, 5, []int{71663, 153221}},
+		{2, 1, []int{}},
+		{0, 0, []int{}},
+		{0, -1, []int{71663, 153221}},
+		{1, -1, []int{153221}},
+	}
+
+	for _, test := range tests {
+		series, err := client.SearchSeriesPage(context.Background(), "The Simpsons", test.offset, test.limit, "en")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []int{}
+		for _, s := range series {
+			got = append(got, s.ID)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Offset '%d' with limit '%d' expected '%v' got '%v'", test.offset, test.limit, test.want, got)
+		}
+	}
+}
+
+func TestSeasonEpisodes(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/all/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/series_71663_all_en.xml")
+	})
+
+	episodes, err := client.SeasonEpisodes(context.Background(), 71663, 1, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(episodes) != 13 {
+		t.Errorf("Expected '13' episodes got '%d'", len(episodes))
+	}
+	for i, ep := range episodes {
+		if ep.SeasonNumber != 1 || ep.EpisodeNumber != i+1 {
+			t.Errorf("Expected 'S01E%02d' got '%s'", i+1, ep)
+		}
+	}
+
+	if _, err := client.SeasonEpisodes(context.Background(), 71663, 99, "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Deduplicate = true
+
+	var mu sync.Mutex
+	requests := 0
+	release := make(chan struct{})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		<-release
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			langs, err := client.Languages(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(langs) == 0 {
+				t.Error("Expected languages from the shared response")
+			}
+		}()
+	}
+
+	// Give every call time to join the one in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("Expected '1' request got '%d'", requests)
+	}
+}
+
+func TestDeduplicateCancel(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Deduplicate = true
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.Languages(ctx)
+		first <- err
+	}()
+	<-started
+
+	second := make(chan error, 1)
+	go func() {
+		langs, err := client.Languages(context.Background())
+		if err == nil && len(langs) == 0 {
+			err = errors.New("no languages in the shared response")
+		}
+		second <- err
+	}()
+
+	// Give the second call time to join the one in flight
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Errorf("Expected '%v' for the cancelled caller got '%v'", context.Canceled, err)
+	}
+
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("Expected the second caller to succeed got '%v'", err)
+	}
+}
+
+func TestDeduplicateAllCancel(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Deduplicate = true
+
+	started := make(chan struct{}, 1)
+	cancelled := make(chan struct{})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Languages(ctx); err != context.Canceled {
+				t.Errorf("Expected '%v' got '%v'", context.Canceled, err)
+			}
+		}()
+	}
+	<-started
+
+	// Give every call time to join the one in flight
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the shared request to be cancelled once every caller gave up")
+	}
+}
+
+func TestSeriesForEpisode(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	handleSeries(71663)
+
+	series, err := client.SeriesForEpisode(context.Background(), &Episode{ID: 55452, SeriesID: 71663}, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.ID != 71663 {
+		t.Errorf("Expected series '71663' got '%d'", series.ID)
+	}
+
+	if _, err := client.SeriesForEpisode(context.Background(), &Episode{ID: 55452}, "en"); err != ErrNoSeriesID {
+		t.Errorf("Expected '%v' got '%v'", ErrNoSeriesID, err)
+	}
+}
+
+func TestNormalizeIMDBID(t *testing.T) {
+	tests := map[string]string{
+		"tt0903747":   "tt0903747",
+		" TT0903747 ": "tt0903747",
+		"0903747":     "tt0903747",
+		"903747":      "tt0903747",
+		"tt10048342":  "tt10048342",
+	}
+	for id, want := range tests {
+		got, err := normalizeIMDBID(id)
+		if err != nil {
+			t.Errorf("IMDb ID '%s' unexpected error '%v'", id, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("IMDb ID '%s' expected '%s' got '%s'", id, want, got)
+		}
+	}
+
+	for _, id := range []string{"", "tt", "tt09o3747", "nm0000123"} {
+		if _, err := normalizeIMDBID(id); !errors.Is(err, ErrInvalidIMDBID) {
+			t.Errorf("IMDb ID '%s' expected '%v' got '%v'", id, ErrInvalidIMDBID, err)
+		}
+	}
+}
+
+func TestOnRawResponse(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	doc := `<?xml version="1.0" encoding="UTF-8" ?><Data><Series><id>1</id><Runtime>half an hour</Runtime></Series></Data>`
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, doc)
+	})
+
+	var gotURL, gotBody string
+	client.OnRawResponse = func(url string, body []byte) {
+		gotURL = url
+		gotBody = string(body)
+	}
+
+	if _, err := client.SeriesByID(context.Background(), 1, "en"); err == nil {
+		t.Fatal("Expected an error for a malformed runtime")
+	}
+	if want := fmt.Sprintf("%s/api/%s/series/1/en.xml", server.URL, apiKey); gotURL != want {
+		t.Errorf("Expected url '%s' got '%s'", want, gotURL)
+	}
+	if gotBody != doc {
+		t.Errorf("Expected body '%s' got '%s'", doc, gotBody)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2015, time.February, 17, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"120":                           2 * time.Minute,
+		" 5 ":                           5 * time.Second,
+		"-1":                            0,
+		"Tue, 17 Feb 2015 12:00:30 GMT": 30 * time.Second,
+		"Tue, 17 Feb 2015 11:00:00 GMT": 0,
+		"soon":                          0,
+	}
+	for header, want := range tests {
+		if got := retryAfter(header, now); got != want {
+			t.Errorf("Retry-After '%s' expected '%s' got '%s'", header, want, got)
+		}
+	}
+}
+
+func TestRetryTooManyRequests(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.MaxRetries = 1
+	client.RetryBackoff = time.Millisecond
+
+	requests := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/languages.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		http.ServeFile(w, r, "testdata/languages.xml")
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	if _, err := client.Languages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected '2' requests got '%d'", requests)
+	}
+
+	// A long Retry-After still gives way to the context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.SeriesByID(ctx, 1, "en"); err != context.DeadlineExceeded {
+		t.Errorf("Expected '%v' got '%v'", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the retry to be abandoned with the context got '%s'", elapsed)
+	}
+}
+
+func TestSearchSeriesDedupe(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Data>
+<Series><id>71663</id><language>en</language></Series>
+<Series><id>153221</id><language>en</language></Series>
+<Series><id>71663</id><language>de</language></Series>
+</Data>`)
+	})
+
+	series, err := client.SearchSeries(context.Background(), "The Simpsons", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 {
+		t.Fatalf("Expected '2' series got '%d'", len(series))
+	}
+	if series[0].ID != 71663 || series[0].Language != "en" || series[1].ID != 153221 {
+		t.Errorf("Expected the first of each series to be kept got '%v'", series)
+	}
+}
+
+func TestSearchSeriesRanked(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/GetSeries.php", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Data>
+<Series><id>153221</id><SeriesName>Jessica Simpson's The Price of Beauty</SeriesName></Series>
+<Series><id>71663</id><SeriesName>The Simpsons</SeriesName></Series>
+</Data>`)
+	})
+
+	series, err := client.SearchSeriesRanked(context.Background(), "The Simpsons", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 {
+		t.Fatalf("Expected '2' series got '%d'", len(series))
+	}
+	if series[0].ID != 71663 || series[0].Score != 1 || series[1].ID != 153221 {
+		t.Errorf("Expected the exact match first got '%v'", series)
+	}
+}
+
+func TestSeriesEverything(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, path := range map[string]string{
+		"en.xml":      "testdata/series_71663_all_en.xml",
+		"actors.xml":  "testdata/series_71663_actors.xml",
+		"banners.xml": "testdata/series_71663_banners.xml",
+	} {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/all/en.zip", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buf.Bytes())
+	})
+
+	full, err := client.SeriesEverything(context.Background(), 71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, file := range map[string]string{
+		"all/en.xml":  "testdata/series_71663_all_en.xml",
+		"actors.xml":  "testdata/series_71663_actors.xml",
+		"banners.xml": "testdata/series_71663_banners.xml",
+	} {
+		file := file
+		mux.HandleFunc(fmt.Sprintf("/api/%s/series/71663/%s", apiKey, path), func(w http.ResponseWriter, r *http.Request) {
+			http.ServeFile(w, r, file)
+		})
+	}
+
+	series, episodes, err := client.SeriesAllByID(context.Background(), 71663, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actors, err := client.ActorsBySeries(context.Background(), 71663)
+	if err != nil {
+		t.Fatal(err)
+	}
+	banners, err := client.BannersBySeries(context.Background(), 71663)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &FullSeries{Series: series, Episodes: episodes, Actors: actors, Banners: banners}
+	if !reflect.DeepEqual(want, full) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(want, full))
+	}
+}
+
+func TestSeriesEverythingNotFound(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	var buf bytes.Buffer
+	zip.NewWriter(&buf).Close()
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/all/en.zip", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buf.Bytes())
+	})
+
+	if _, err := client.SeriesEverything(context.Background(), 1, "en"); err != ErrNotFound {
+		t.Errorf("Expected '%v' got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestSearchSeriesIDs(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler(`testdata/GetSeries.php?seriesname=The%20Simpsons`)
+	mux.Handle("/api/GetSeries.php", handler)
+
+	ids, err := client.SearchSeriesIDs(context.Background(), "The Simpsons", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{71663, 153221}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expected IDs '%v' got '%v'", want, ids)
+	}
+}
+
+func TestDecodeEncodings(t *testing.T) {
+	tests := map[string]string{
+		"utf-8 with BOM": "\xef\xbb\xbf<?xml version=\"1.0\" encoding=\"UTF-8\" ?><Data><Series><SeriesName>Caf\xc3\xa9</SeriesName></Series></Data>",
+		"iso-8859-1":     "<?xml version=\"1.0\" encoding=\"ISO-8859-1\" ?><Data><Series><SeriesName>Caf\xe9</SeriesName></Series></Data>",
+	}
+
+	for name, doc := range tests {
+		response := struct {
+			XMLName xml.Name `xml:"Data"`
+			Series  Series
+		}{}
+		if err := decode(context.Background(), strings.NewReader(doc), &response); err != nil {
+			t.Errorf("Decoding %s failed: %v", name, err)
+			continue
+		}
+		if response.Series.Name != "Café" {
+			t.Errorf("Decoding %s expected 'Café' got '%s'", name, response.Series.Name)
+		}
+	}
+}
+
+func TestSeriesGenre(t *testing.T) {
+	s := Series{Genre: pipeList{"Animation", "Comedy"}}
+	if got := s.PrimaryGenre(); got != "Animation" {
+		t.Errorf("Expected primary genre 'Animation' got '%s'", got)
+	}
+	if got := (Series{}).PrimaryGenre(); got != "" {
+		t.Errorf("Expected no primary genre got '%s'", got)
+	}
+
+	tests := map[string]bool{
+		"comedy":    true,
+		"ANIMATION": true,
+		"Drama":     false,
+		"":          false,
+	}
+	for genre, want := range tests {
+		if got := s.HasGenre(genre); got != want {
+			t.Errorf("HasGenre '%s' expected '%t' got '%t'", genre, want, got)
+		}
+	}
+}
+
+func TestIMDBURL(t *testing.T) {
+	if got, want := (&Series{IMDBID: "tt0096697"}).IMDBURL(), "https://www.imdb.com/title/tt0096697/"; got != want {
+		t.Errorf("Expected '%s' got '%s'", want, got)
+	}
+	if got, want := (&SeriesSummary{IMDBID: "0096697"}).IMDBURL(), "https://www.imdb.com/title/tt0096697/"; got != want {
+		t.Errorf("Expected '%s' got '%s'", want, got)
+	}
+	if got := (&Episode{}).IMDBURL(); got != "" {
+		t.Errorf("Expected no URL for a blank ID got '%s'", got)
+	}
+}
+
+func TestAPIDeprecated(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/1/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/%s/series/2/en.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, "<html><body>This API has been deprecated.  Please use the new API.</body></html>")
+	})
+
+	for _, id := range []int{1, 2} {
+		if _, err := client.SeriesByID(context.Background(), id, "en"); !errors.Is(err, ErrAPIDeprecated) {
+			t.Errorf("Series '%d' expected '%v' got '%v'", id, ErrAPIDeprecated, err)
+		}
+	}
+}
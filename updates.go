@@ -0,0 +1,117 @@
+package tvdb
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// UpdatePeriod is the window of time covered by an Updates request.
+type UpdatePeriod string
+
+const (
+	UpdatesDay   = UpdatePeriod("day")
+	UpdatesWeek  = UpdatePeriod("week")
+	UpdatesMonth = UpdatePeriod("month")
+)
+
+// SeriesUpdate records a series that has changed.
+type SeriesUpdate struct {
+	ID          int      `xml:"id" json:"id"`
+	LastUpdated unixTime `xml:"time" json:"last_updated"`
+}
+
+// EpisodeUpdate records an episode that has changed.
+type EpisodeUpdate struct {
+	ID          int      `xml:"id" json:"id"`
+	SeriesID    int      `xml:"Series" json:"series_id"`
+	LastUpdated unixTime `xml:"time" json:"last_updated"`
+}
+
+// BannerUpdate records a banner that has been added or changed.  Banners
+// have no ID of their own so they are identified by their path.
+type BannerUpdate struct {
+	SeriesID    int      `xml:"Series" json:"series_id"`
+	Path        string   `xml:"path" json:"path"`
+	Type        string   `xml:"type" json:"type"`
+	Format      string   `xml:"format" json:"format"`
+	Language    string   `xml:"language" json:"language"`
+	Season      nullInt  `xml:"SeasonNum" json:"season"`
+	LastUpdated unixTime `xml:"time" json:"last_updated"`
+}
+
+// Updates is the list of series, episodes, and banners that changed during an
+// UpdatePeriod along with the server's time when the list was generated.
+type Updates struct {
+	Time     unixTime        `xml:"time,attr" json:"time"`
+	Series   []SeriesUpdate  `xml:"Series" json:"series"`
+	Episodes []EpisodeUpdate `xml:"Episode" json:"episodes"`
+	Banners  []BannerUpdate  `xml:"Banner" json:"banners"`
+}
+
+// Updates gets everything that has changed on TheTVDB during the given period.
+// The response is never cached.
+// See: https://thetvdb.com/wiki/index.php?title=API:Update_Records
+func (c *Client) Updates(ctx context.Context, period UpdatePeriod) (*Updates, error) {
+	ctx = withEndpoint(ctx, "Updates")
+	u := c.staticAPIURL(fmt.Sprintf("updates/updates_%s.xml", period))
+	response := struct {
+		XMLName xml.Name `xml:"Data"`
+		Updates
+	}{}
+	if err := c.fetchResponse(ctx, u.String(), &response, nil); err != nil {
+		return nil, err
+	}
+	return &response.Updates, nil
+}
+
+// ServerTime gets TheTVDB's current time.  Store it before fetching updates so
+// the next UpdatesSince call doesn't depend on the local clock.  The response
+// is never cached.
+// See: https://thetvdb.com/wiki/index.php?title=API:Update_Records
+func (c *Client) ServerTime(ctx context.Context) (time.Time, error) {
+	ctx = withEndpoint(ctx, "ServerTime")
+	u := c.apiURL("Updates.php", url.Values{"type": []string{"none"}})
+	response := struct {
+		XMLName xml.Name `xml:"Items"`
+		Time    unixTime `xml:"Time"`
+	}{}
+	if err := c.fetchResponse(ctx, u.String(), &response, nil); err != nil {
+		return time.Time{}, err
+	}
+	return response.Time.Time, nil
+}
+
+// UpdatesSince gets the series and episodes that have changed on TheTVDB since
+// the given time, usually a time previously returned by ServerTime or
+// Updates.Time.  Only IDs are returned so LastUpdated and SeriesID are not
+// set and Banners is always empty.  The response is never cached.
+// See: https://thetvdb.com/wiki/index.php?title=API:Update_Records
+func (c *Client) UpdatesSince(ctx context.Context, since time.Time) (*Updates, error) {
+	ctx = withEndpoint(ctx, "UpdatesSince")
+	u := c.apiURL("Updates.php", url.Values{
+		"type": []string{"all"},
+		"time": []string{strconv.FormatInt(since.Unix(), 10)},
+	})
+	response := struct {
+		XMLName  xml.Name `xml:"Items"`
+		Time     unixTime `xml:"Time"`
+		Series   []int    `xml:"Series"`
+		Episodes []int    `xml:"Episode"`
+	}{}
+	if err := c.fetchResponse(ctx, u.String(), &response, nil); err != nil {
+		return nil, err
+	}
+
+	updates := &Updates{Time: response.Time}
+	for _, id := range response.Series {
+		updates.Series = append(updates.Series, SeriesUpdate{ID: id})
+	}
+	for _, id := range response.Episodes {
+		updates.Episodes = append(updates.Episodes, EpisodeUpdate{ID: id})
+	}
+	return updates, nil
+}
@@ -0,0 +1,125 @@
+package tvdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestUpdates(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/updates_day.xml")
+	mux.Handle(fmt.Sprintf("/api/%s/updates/updates_day.xml", apiKey), handler)
+
+	updates, err := client.Updates(context.Background(), UpdatesDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Updates{
+		Time: unixTime{time.Unix(1424177783, 0).UTC()},
+		Series: []SeriesUpdate{
+			{ID: 71663, LastUpdated: unixTime{time.Unix(1424174914, 0).UTC()}},
+			{ID: 73871, LastUpdated: unixTime{time.Unix(1424176273, 0).UTC()}},
+		},
+		Episodes: []EpisodeUpdate{
+			{ID: 55452, SeriesID: 71663, LastUpdated: unixTime{time.Unix(1424174914, 0).UTC()}},
+			{ID: 4350173, SeriesID: 71663, LastUpdated: unixTime{time.Unix(1424175012, 0).UTC()}},
+		},
+		Banners: []BannerUpdate{
+			{
+				SeriesID:    71663,
+				Path:        "seasons/71663-1-3.jpg",
+				Type:        "season",
+				Format:      "standard",
+				Language:    "en",
+				Season:      NullInt(1),
+				LastUpdated: unixTime{time.Unix(1424175430, 0).UTC()},
+			},
+			{
+				SeriesID:    73871,
+				Path:        "fanart/original/73871-12.jpg",
+				Type:        "fanart",
+				Format:      "1920x1080",
+				Season:      NulInt,
+				LastUpdated: unixTime{time.Unix(1424176273, 0).UTC()},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(want, updates))
+	}
+}
+
+func TestServerTime(t *testing.T) {
+	client := setup()
+	defer server.Close()
+
+	mux.HandleFunc("/api/Updates.php", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{"type": "none"})
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" ?><Items><Time>1424177783</Time></Items>`)
+	})
+
+	got, err := client.ServerTime(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Unix(1424177783, 0).UTC(); !got.Equal(want) {
+		t.Errorf("Expected '%s' got '%s'", want, got)
+	}
+}
+
+func TestUpdatesNotCached(t *testing.T) {
+	client := setup()
+	defer server.Close()
+	client.Cache = NewMemoryCache(time.Hour)
+
+	requests := 0
+	mux.HandleFunc(fmt.Sprintf("/api/%s/updates/updates_day.xml", apiKey), func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.ServeFile(w, r, "testdata/updates_day.xml")
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Updates(context.Background(), UpdatesDay); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected '2' requests got '%d'", requests)
+	}
+}
+
+func TestUpdatesSince(t *testing.T) {
+	client := setup()
+	defer teardown()
+
+	handler = newFileHandler("testdata/updates_since.xml")
+	mux.HandleFunc("/api/Updates.php", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{"type": "all", "time": "1424174914"})
+		handler.ServeHTTP(w, r)
+	})
+
+	updates, err := client.UpdatesSince(context.Background(), time.Unix(1424174914, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Updates{
+		Time:     unixTime{time.Unix(1424177783, 0).UTC()},
+		Series:   []SeriesUpdate{{ID: 71663}, {ID: 73871}},
+		Episodes: []EpisodeUpdate{{ID: 55452}, {ID: 4350173}},
+	}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("Response does not match.  \n%s", pretty.Compare(want, updates))
+	}
+}